@@ -0,0 +1,222 @@
+package mwm
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+// ApproximationAlgorithm selects the algorithm of MaxWeightMatchingApprox
+type ApproximationAlgorithm int
+
+const (
+	// GreedyApproximation matches the edges in order of decreasing weight while both endpoints are
+	// free. It finds at least half the optimum weight in O(m log m)
+	GreedyApproximation ApproximationAlgorithm = iota
+	// PathGrowingApproximation grows paths along the heaviest remaining edge of every vertex and splits
+	// them alternately into two matchings, of which it returns the heavier one. It finds at least half
+	// the optimum weight in O(m), as shown by Drake and Hougardy
+	PathGrowingApproximation
+)
+
+// WeightedApproximateResult describes a matching found by an approximation algorithm, with weights of type W
+type WeightedApproximateResult[W any] struct {
+	// WeightedMatchingResult holds the matching; VertexDuals, Blossoms and Stats are nil
+	WeightedMatchingResult[W]
+	// UpperBound is at least the weight of a maximum weight matching, so UpperBound - TotalWeight
+	// bounds how far the matching is from the optimum
+	UpperBound W
+}
+
+// ApproximateResult describes a matching found by an approximation algorithm
+type ApproximateResult = WeightedApproximateResult[int64]
+
+// MaxWeightMatchingApprox returns a matching of large weight found by algorithm, together with an
+// upper bound on the optimum weight. Like MaxWeightMatching without maximum cardinality mode, no
+// edge of negative weight is matched.
+//
+// If the upper bound does not fit in int64, it is reported as math.MaxInt64 together with ErrWeightOverflow
+func (mwm *MaximumWeightedMatching) MaxWeightMatchingApprox(edges []GraphEdge, algorithm ApproximationAlgorithm) (*ApproximateResult, error) {
+	if err := validateEdges(Int64Arithmetic{}, edges); err != nil {
+		return nil, err
+	}
+	if err := validateApproximation(algorithm); err != nil {
+		return nil, err
+	}
+
+	var upperBound int64
+	var boundErr error
+	result, err := int64Fallback(edges,
+		func() (*MatchingResult, error) {
+			approx, err := approximate(Int64Arithmetic{}, edges, algorithm)
+			if approx == nil {
+				return nil, err
+			}
+			upperBound = approx.UpperBound
+			return &approx.WeightedMatchingResult, err
+		},
+		func(edges []WeightedEdge[*big.Int]) (*WeightedMatchingResult[*big.Int], error) {
+			approx, err := approximate(BigIntArithmetic{}, edges, algorithm)
+			if approx == nil {
+				return nil, err
+			}
+			upperBound, boundErr = math.MaxInt64, ErrWeightOverflow
+			if approx.UpperBound.IsInt64() {
+				upperBound, boundErr = approx.UpperBound.Int64(), nil
+			}
+			return &approx.WeightedMatchingResult, err
+		})
+	if result == nil {
+		return nil, err
+	}
+	if err == nil {
+		err = boundErr
+	}
+	return &ApproximateResult{WeightedMatchingResult: *result, UpperBound: upperBound}, err
+}
+
+// MaxWeightMatchingApproxWith is MaxWeightMatchingApprox for weights of type W, computing with the operations of ar
+func MaxWeightMatchingApproxWith[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], algorithm ApproximationAlgorithm) (*WeightedApproximateResult[W], error) {
+	if err := validateEdges(ar, edges); err != nil {
+		return nil, err
+	}
+	if err := validateApproximation(algorithm); err != nil {
+		return nil, err
+	}
+	return approximate(ar, edges, algorithm)
+}
+
+// validateApproximation returns an error for an unknown algorithm
+func validateApproximation(algorithm ApproximationAlgorithm) error {
+	switch algorithm {
+	case GreedyApproximation, PathGrowingApproximation:
+		return nil
+	}
+	return fmt.Errorf("mwm: unknown approximation algorithm %d", algorithm)
+}
+
+// approximate runs a known algorithm on validated edges
+func approximate[W any](ar Arithmetic[W], edges []WeightedEdge[W], algorithm ApproximationAlgorithm) (result *WeightedApproximateResult[W], err error) {
+	defer recoverFailure(&err)
+
+	var sol *solution[W]
+	var upperBound W
+	switch algorithm {
+	case GreedyApproximation:
+		g := newUnweightedGraph(edges)
+		weight := greedyMatching(ar, g, edges)
+		upperBound = ar.Double(weight)
+		sol = graphSolution[W](g)
+	default:
+		g := newUnweightedGraph(edges)
+		upperBound = pathGrowingMatching(ar, g, edges)
+		sol = graphSolution[W](g)
+	}
+
+	// Half the heaviest edge at every vertex is a feasible dual solution, which may bound the optimum better
+	if vertexBound := vertexWeightBound(ar, edges); ar.Cmp(vertexBound, upperBound) < 0 {
+		upperBound = vertexBound
+	}
+	return &WeightedApproximateResult[W]{
+		WeightedMatchingResult: *newMatchingResult(ar, edges, sol, false),
+		UpperBound:             upperBound,
+	}, nil
+}
+
+// greedyMatching matches the edges of g with positive weight in order of decreasing weight, and
+// returns the weight of the matching. Every edge of the optimum shares a vertex with a matched edge
+// of at least its weight, so the optimum is at most twice the returned weight
+func greedyMatching[W any](ar Arithmetic[W], g *unweightedGraph, edges []WeightedEdge[W]) W {
+	order := make([]int, 0, len(edges))
+	for k, edge := range edges {
+		if ar.Cmp(edge.Weight, ar.Zero()) > 0 {
+			order = append(order, k)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return ar.Cmp(edges[order[a]].Weight, edges[order[b]].Weight) > 0
+	})
+
+	weight := ar.Zero()
+	for _, k := range order {
+		u, v := int(edges[k].Node1), int(edges[k].Node2)
+		if g.mate[u] == -1 && g.mate[v] == -1 {
+			g.match(u, v, k)
+			weight = ar.Add(weight, edges[k].Weight)
+		}
+	}
+	return weight
+}
+
+// pathGrowingMatching matches g with the path growing algorithm and returns the total weight of
+// both matchings it builds. Every edge of the optimum is outweighed by the edge that the path took
+// from whichever of its endpoints was removed first, so that total bounds the optimum
+func pathGrowingMatching[W any](ar Arithmetic[W], g *unweightedGraph, edges []WeightedEdge[W]) W {
+	removed := make([]bool, g.nvertex)
+	var matchings [2][]int
+	weights := [2]W{ar.Zero(), ar.Zero()}
+	for start := 0; start < g.nvertex; start++ {
+		i := 0
+		for x := start; !removed[x]; {
+			best := -1
+			for p := g.incidentstart[x]; p < g.incidentstart[x+1]; p++ {
+				k := g.incident[p]
+				if removed[g.neighbor[p]] || ar.Cmp(edges[k].Weight, ar.Zero()) <= 0 {
+					continue
+				}
+				if best == -1 || ar.Cmp(edges[k].Weight, edges[g.incident[best]].Weight) > 0 {
+					best = p
+				}
+			}
+			removed[x] = true
+			if best == -1 {
+				break
+			}
+			k := g.incident[best]
+			matchings[i] = append(matchings[i], k)
+			weights[i] = ar.Add(weights[i], edges[k].Weight)
+			i = 1 - i
+			x = g.neighbor[best]
+		}
+	}
+
+	heavier := 0
+	if ar.Cmp(weights[1], weights[0]) > 0 {
+		heavier = 1
+	}
+	for _, k := range matchings[heavier] {
+		g.match(int(edges[k].Node1), int(edges[k].Node2), k)
+	}
+	return ar.Add(weights[0], weights[1])
+}
+
+// vertexWeightBound returns half the sum over all vertices of their heaviest positive edge, rounded
+// up. These halves cover every edge, so they bound the optimum like a dual solution
+func vertexWeightBound[W any](ar Arithmetic[W], edges []WeightedEdge[W]) W {
+	heaviest := make(map[int64]W)
+	for _, edge := range edges {
+		if ar.Cmp(edge.Weight, ar.Zero()) <= 0 {
+			continue
+		}
+		for _, v := range [2]int64{edge.Node1, edge.Node2} {
+			if w, ok := heaviest[v]; !ok || ar.Cmp(edge.Weight, w) > 0 {
+				heaviest[v] = edge.Weight
+			}
+		}
+	}
+	sum := ar.Zero()
+	for _, w := range heaviest {
+		sum = ar.Add(sum, w)
+	}
+	return ceilHalf(ar, sum)
+}
+
+// ceilHalf returns a/2 for a non-negative a, rounded up
+func ceilHalf[W any](ar Arithmetic[W], a W) W {
+	half, exact := ar.Half(a)
+	if !exact {
+		half = ar.Sub(a, half)
+	}
+	return half
+}
@@ -0,0 +1,129 @@
+package mwm
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// checkApproximation - helper function checking that an approximate matching is valid and within its bounds
+func checkApproximation(t *testing.T, i int, edges []GraphEdge, result *ApproximateResult, optimum int64, ratio float64) {
+	t.Helper()
+	var total int64
+	for p, pair := range result.Pairs {
+		if result.Mate[pair.First] != pair.Second || result.Mate[pair.Second] != pair.First {
+			t.Fatalf("Iteration %d: inconsistent pair %v", i, pair)
+		}
+		edge := edges[result.EdgeIndices[p]]
+		if min(edge.Node1, edge.Node2) != pair.First || max(edge.Node1, edge.Node2) != pair.Second {
+			t.Fatalf("Iteration %d: pair %v does not match edge %v", i, pair, edge)
+		}
+		total += edge.Weight
+	}
+	if total != result.TotalWeight {
+		t.Fatalf("Iteration %d: expected total weight %d, got %d", i, total, result.TotalWeight)
+	}
+	if result.TotalWeight > optimum || result.UpperBound < optimum {
+		t.Fatalf("Iteration %d: optimum %d outside of [%d, %d]\nedges: %v", i, optimum, result.TotalWeight, result.UpperBound, edges)
+	}
+	if float64(result.TotalWeight) < ratio*float64(optimum) {
+		t.Fatalf("Iteration %d: weight %d is below %v times the optimum %d\nedges: %v", i, result.TotalWeight, ratio, optimum, edges)
+	}
+}
+
+// TestApproximateRandom - test the approximation ratios and upper bounds on random graphs against exhaustive search
+func TestApproximateRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(40))
+	matcher := NewMaximumWeightedMatching()
+	for i := 0; i < 500; i++ {
+		nvertex := 2 + rng.Intn(13)
+		edges := randomEdges(rng, nvertex, 0.1+0.6*rng.Float64(), -20, 1+rng.Int63n(1000))
+		_, optimum := bruteForceMatching(nvertex, edges, false)
+		for _, algorithm := range []ApproximationAlgorithm{GreedyApproximation, PathGrowingApproximation} {
+			result, err := matcher.MaxWeightMatchingApprox(edges, algorithm)
+			if err != nil {
+				t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+			}
+			checkApproximation(t, i, edges, result, optimum, 0.5)
+		}
+	}
+}
+
+// TestApproximateFloat - test the approximations with floating-point weights
+func TestApproximateFloat(t *testing.T) {
+	rng := rand.New(rand.NewSource(43))
+	matcher := NewMaximumWeightedMatching()
+	ar := Float64Arithmetic{Epsilon: DefaultEpsilon}
+	for i := 0; i < 100; i++ {
+		nvertex := 2 + rng.Intn(13)
+		edges := make([]FloatGraphEdge, 0)
+		for _, edge := range randomEdges(rng, nvertex, 0.5, 0, 1000) {
+			edges = append(edges, FloatGraphEdge{Node1: edge.Node1, Node2: edge.Node2, Weight: float64(edge.Weight) / 7})
+		}
+		_, optimum := bruteForceMatching(nvertex, edges, false)
+		for _, algorithm := range []ApproximationAlgorithm{GreedyApproximation, PathGrowingApproximation} {
+			result, err := MaxWeightMatchingApproxWith(matcher, ar, edges, algorithm)
+			if err != nil {
+				t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+			}
+			if ar.Cmp(result.TotalWeight, optimum) > 0 || ar.Cmp(result.UpperBound, optimum) < 0 {
+				t.Fatalf("Iteration %d: optimum %v outside of [%v, %v]", i, optimum, result.TotalWeight, result.UpperBound)
+			}
+		}
+	}
+}
+
+// TestApproximateLargeWeights - test the approximations with weights beyond SafeWeightLimit
+func TestApproximateLargeWeights(t *testing.T) {
+	edges := []GraphEdge{
+		{Node1: 0, Node2: 1, Weight: SafeWeightLimit + 3},
+		{Node1: 1, Node2: 2, Weight: SafeWeightLimit + 5},
+		{Node1: 2, Node2: 3, Weight: SafeWeightLimit + 4},
+	}
+	matcher := NewMaximumWeightedMatching()
+	for _, algorithm := range []ApproximationAlgorithm{GreedyApproximation, PathGrowingApproximation} {
+		result, err := matcher.MaxWeightMatchingApprox(edges, algorithm)
+		if err != nil {
+			t.Fatalf("Algorithm %d: unexpected error: %v", algorithm, err)
+		}
+		checkApproximation(t, int(algorithm), edges, result, 2*SafeWeightLimit+7, 0.5)
+	}
+}
+
+// TestApproximateInvalid - test that unknown algorithms and invalid edges are reported
+func TestApproximateInvalid(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{{Node1: 0, Node2: 1, Weight: 1}}
+	if _, err := matcher.MaxWeightMatchingApprox(edges, ApproximationAlgorithm(7)); err == nil {
+		t.Error("Expected an error for an unknown algorithm")
+	}
+	if _, err := matcher.MaxWeightMatchingApprox([]GraphEdge{{Node1: 1, Node2: 1, Weight: 1}}, GreedyApproximation); !errors.Is(err, ErrSelfLoop) {
+		t.Errorf("Expected ErrSelfLoop, got %v", err)
+	}
+	result, err := matcher.MaxWeightMatchingApprox(nil, PathGrowingApproximation)
+	if err != nil || result.Cardinality != 0 || result.UpperBound != 0 {
+		t.Errorf("Expected an empty matching, got %v with error %v", result, err)
+	}
+}
+
+// BenchmarkApproximateGreedy - benchmark the greedy approximation on the graph of BenchmarkScanEngine
+func BenchmarkApproximateGreedy(b *testing.B) {
+	benchmarkApproximate(b, GreedyApproximation)
+}
+
+// BenchmarkApproximatePathGrowing - benchmark the path growing approximation on the graph of BenchmarkScanEngine
+func BenchmarkApproximatePathGrowing(b *testing.B) {
+	benchmarkApproximate(b, PathGrowingApproximation)
+}
+
+// benchmarkApproximate - helper function running an approximation on the graph of benchmarkSparse
+func benchmarkApproximate(b *testing.B, algorithm ApproximationAlgorithm) {
+	rng := rand.New(rand.NewSource(35))
+	edges := randomEdges(rng, 3000, 0.002, 1, 1000000)
+	matcher := NewMaximumWeightedMatching()
+	for b.Loop() {
+		if _, err := matcher.MaxWeightMatchingApprox(edges, algorithm); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package mwm
+
+import (
+	"math"
+	"math/big"
+)
+
+// DefaultEpsilon is the tolerance used for floating-point weights when MaximumWeightedMatching.Epsilon is zero
+const DefaultEpsilon = 1e-9
+
+// Arithmetic describes the operations the algorithm performs on weights and dual variables of type W.
+// The algorithm never modifies values in place, so W may be a pointer type such as *big.Rat
+type Arithmetic[W any] interface {
+	Zero() W
+	Add(a, b W) W
+	Sub(a, b W) W
+	// Double returns 2*a
+	Double(a W) W
+	// Half returns a/2 and whether the division is exact
+	Half(a W) (W, bool)
+	// Cmp returns -1, 0 or +1 as a is less than, equal to or greater than b
+	Cmp(a, b W) int
+	// Valid reports whether w can be used as an edge weight
+	Valid(w W) bool
+}
+
+// Int32Arithmetic is the exact arithmetic on int32 weights.
+// An operation that overflows panics with ErrWeightOverflow, which the solver reports as an error
+type Int32Arithmetic struct{}
+
+func (Int32Arithmetic) Zero() int32             { return 0 }
+func (ar Int32Arithmetic) Double(a int32) int32 { return ar.Add(a, a) }
+func (Int32Arithmetic) Valid(w int32) bool      { return true }
+
+func (Int32Arithmetic) Add(a, b int32) int32 {
+	c := a + b
+	if (a^c)&(b^c) < 0 {
+		panic(ErrWeightOverflow)
+	}
+	return c
+}
+
+func (Int32Arithmetic) Sub(a, b int32) int32 {
+	c := a - b
+	if (a^b)&(a^c) < 0 {
+		panic(ErrWeightOverflow)
+	}
+	return c
+}
+
+func (Int32Arithmetic) Half(a int32) (int32, bool) {
+	return a / 2, a%2 == 0
+}
+
+func (Int32Arithmetic) Cmp(a, b int32) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+// Int64Arithmetic is the exact arithmetic on int64 weights.
+// An operation that overflows panics with ErrWeightOverflow, which the solver reports as an error
+type Int64Arithmetic struct{}
+
+func (Int64Arithmetic) Zero() int64             { return 0 }
+func (ar Int64Arithmetic) Double(a int64) int64 { return ar.Add(a, a) }
+func (Int64Arithmetic) Valid(w int64) bool      { return true }
+
+func (Int64Arithmetic) Add(a, b int64) int64 {
+	c := a + b
+	if (a^c)&(b^c) < 0 {
+		panic(ErrWeightOverflow)
+	}
+	return c
+}
+
+func (Int64Arithmetic) Sub(a, b int64) int64 {
+	c := a - b
+	if (a^b)&(a^c) < 0 {
+		panic(ErrWeightOverflow)
+	}
+	return c
+}
+
+func (Int64Arithmetic) Half(a int64) (int64, bool) {
+	return a / 2, a%2 == 0
+}
+
+func (Int64Arithmetic) Cmp(a, b int64) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+// Float64Arithmetic treats float64 values that differ by at most Epsilon as equal
+type Float64Arithmetic struct {
+	Epsilon float64
+}
+
+func (Float64Arithmetic) Zero() float64            { return 0 }
+func (Float64Arithmetic) Add(a, b float64) float64 { return a + b }
+func (Float64Arithmetic) Sub(a, b float64) float64 { return a - b }
+func (Float64Arithmetic) Double(a float64) float64 { return 2 * a }
+
+func (Float64Arithmetic) Half(a float64) (float64, bool) {
+	return a / 2, true
+}
+
+func (ar Float64Arithmetic) Cmp(a, b float64) int {
+	if math.Abs(a-b) <= ar.Epsilon {
+		return 0
+	}
+	if a < b {
+		return -1
+	}
+	return 1
+}
+
+func (Float64Arithmetic) Valid(w float64) bool {
+	return !math.IsNaN(w) && !math.IsInf(w, 0)
+}
+
+// BigIntArithmetic is the exact arithmetic on arbitrary precision integer weights
+type BigIntArithmetic struct{}
+
+func (BigIntArithmetic) Zero() *big.Int             { return new(big.Int) }
+func (BigIntArithmetic) Add(a, b *big.Int) *big.Int { return new(big.Int).Add(a, b) }
+func (BigIntArithmetic) Sub(a, b *big.Int) *big.Int { return new(big.Int).Sub(a, b) }
+func (BigIntArithmetic) Double(a *big.Int) *big.Int { return new(big.Int).Lsh(a, 1) }
+func (BigIntArithmetic) Cmp(a, b *big.Int) int      { return a.Cmp(b) }
+func (BigIntArithmetic) Valid(w *big.Int) bool      { return w != nil }
+
+func (BigIntArithmetic) Half(a *big.Int) (*big.Int, bool) {
+	return new(big.Int).Quo(a, big.NewInt(2)), a.Bit(0) == 0
+}
+
+// RatArithmetic is the exact arithmetic on rational weights
+type RatArithmetic struct{}
+
+func (RatArithmetic) Zero() *big.Rat             { return new(big.Rat) }
+func (RatArithmetic) Add(a, b *big.Rat) *big.Rat { return new(big.Rat).Add(a, b) }
+func (RatArithmetic) Sub(a, b *big.Rat) *big.Rat { return new(big.Rat).Sub(a, b) }
+func (RatArithmetic) Double(a *big.Rat) *big.Rat { return new(big.Rat).Add(a, a) }
+func (RatArithmetic) Cmp(a, b *big.Rat) int      { return a.Cmp(b) }
+func (RatArithmetic) Valid(w *big.Rat) bool      { return w != nil }
+
+func (RatArithmetic) Half(a *big.Rat) (*big.Rat, bool) {
+	return new(big.Rat).Quo(a, big.NewRat(2, 1)), true
+}
@@ -0,0 +1,90 @@
+package mwm
+
+import (
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// TestInt32Weights - test that int32 weights give the same matching as int64 weights
+func TestInt32Weights(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	matcher := NewMaximumWeightedMatching()
+	for i := 0; i < 100; i++ {
+		edges := randomEdges(rng, 2+rng.Intn(20), 0.3, -100, 1000)
+		edges32 := make([]WeightedEdge[int32], len(edges))
+		for k, edge := range edges {
+			edges32[k] = WeightedEdge[int32]{Node1: edge.Node1, Node2: edge.Node2, Weight: int32(edge.Weight)}
+		}
+		maxCardinality := i%2 == 1
+		result32, err := MaxWeightMatchingWith(matcher, Int32Arithmetic{}, edges32, maxCardinality)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		result64 := matcher.MaxWeightMatchingResult(edges, maxCardinality)
+		if !reflect.DeepEqual(result32.Pairs, result64.Pairs) || int64(result32.TotalWeight) != result64.TotalWeight {
+			t.Fatalf("Iteration %d: int32 gave %v, int64 gave %v", i, result32.Pairs, result64.Pairs)
+		}
+		if err := VerifyOptimumWith(Int32Arithmetic{}, edges32, result32); err != nil {
+			t.Fatalf("Iteration %d: %v", i, err)
+		}
+	}
+}
+
+// TestRationalWeights - test exact rational weights against the scaled integer problem
+func TestRationalWeights(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	matcher := NewMaximumWeightedMatching()
+	const denominator = 7
+	for i := 0; i < 100; i++ {
+		nvertex := 2 + rng.Intn(12)
+		edges := randomEdges(rng, nvertex, 0.5, -20, 40)
+		ratEdges := make([]WeightedEdge[*big.Rat], len(edges))
+		for k, edge := range edges {
+			ratEdges[k] = WeightedEdge[*big.Rat]{Node1: edge.Node1, Node2: edge.Node2, Weight: big.NewRat(edge.Weight, denominator)}
+		}
+		maxCardinality := i%2 == 1
+		result, err := MaxWeightMatchingWith(matcher, RatArithmetic{}, ratEdges, maxCardinality)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		_, weight := bruteForceMatching(nvertex, edges, maxCardinality)
+		if result.TotalWeight.Cmp(big.NewRat(weight, denominator)) != 0 {
+			t.Fatalf("Iteration %d: expected total weight %d/%d, got %v", i, weight, denominator, result.TotalWeight)
+		}
+		if err := VerifyOptimumWith(RatArithmetic{}, ratEdges, result); err != nil {
+			t.Fatalf("Iteration %d: %v", i, err)
+		}
+	}
+}
+
+// TestRationalWeightsInvalid - test that a nil rational weight is rejected
+func TestRationalWeightsInvalid(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []WeightedEdge[*big.Rat]{
+		{Node1: 0, Node2: 1, Weight: nil},
+	}
+	if _, err := MaxWeightMatchingWith(matcher, RatArithmetic{}, edges, false); err == nil {
+		t.Errorf("Expected an error for a nil weight")
+	}
+}
+
+// TestFloatVerifyOptimum - test the certificate for floating-point weights with a tolerance
+func TestFloatVerifyOptimum(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	matcher := NewMaximumWeightedMatching()
+	for i := 0; i < 100; i++ {
+		edges := make([]FloatGraphEdge, 0)
+		for _, edge := range randomEdges(rng, 2+rng.Intn(20), 0.4, 0, 1) {
+			edges = append(edges, FloatGraphEdge{Node1: edge.Node1, Node2: edge.Node2, Weight: rng.Float64()})
+		}
+		result, err := matcher.MaxWeightMatchingFloat(edges, i%2 == 1)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if err := VerifyOptimumWith(Float64Arithmetic{Epsilon: 1e-6}, edges, result); err != nil {
+			t.Fatalf("Iteration %d: %v", i, err)
+		}
+	}
+}
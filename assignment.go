@@ -0,0 +1,154 @@
+package mwm
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// AssignmentOptions controls SolveAssignment
+type AssignmentOptions struct {
+	// Minimize treats the matrix as costs to be minimized instead of profits to be maximized
+	Minimize bool
+	// Partial assigns only the cells that improve the objective. By default as many rows as
+	// possible are assigned, which is min(rows, columns) unless forbidden cells prevent it
+	Partial bool
+	// Forbidden, if not nil, has the shape of the matrix and marks the cells that must not be assigned
+	Forbidden [][]bool
+	// Matching, if not nil, supplies the solver options such as CollectStats
+	Matching *MaximumWeightedMatching
+}
+
+// WeightedAssignment describes an assignment of rows to columns of a matrix with values of type W
+type WeightedAssignment[W any] struct {
+	// RowToColumn maps every row to its assigned column, or to -1 when the row is unassigned
+	RowToColumn []int
+	// ColumnToRow maps every column to its assigned row, or to -1 when the column is unassigned
+	ColumnToRow []int
+	// Total is the sum of the assigned cells
+	Total W
+	// Stats holds the solver statistics if Matching.CollectStats was set, or nil
+	Stats *SolverStats
+}
+
+// Assignment describes an assignment of rows to columns of an int64 matrix
+type Assignment = WeightedAssignment[int64]
+
+// SolveAssignment assigns rows of a rectangular matrix to distinct columns so that the total of
+// the assigned cells is maximal, or minimal with opts.Minimize. Row r and column c become the
+// vertices r and rows+c of a bipartite graph, which MaxWeightBipartiteMatching solves.
+// A ragged matrix or a Forbidden mask of another shape is reported as ErrInvalidMatrix
+func SolveAssignment(matrix [][]int64, opts AssignmentOptions) (*Assignment, error) {
+	edges, isLeft, err := assignmentEdges(Int64Arithmetic{}, matrix, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	mwm := opts.matching()
+	solveOpts := solveOptions{maxCardinality: !opts.Partial}
+	result, err := int64Fallback(edges,
+		func() (*MatchingResult, error) {
+			return solveAssignment(mwm, Int64Arithmetic{}, edges, isLeft, opts.Minimize, solveOpts)
+		},
+		func(edges []WeightedEdge[*big.Int]) (*WeightedMatchingResult[*big.Int], error) {
+			return solveAssignment(mwm, BigIntArithmetic{}, edges, isLeft, opts.Minimize, solveOpts)
+		})
+	if result == nil {
+		return nil, err
+	}
+	return newAssignment(matrix, result), err
+}
+
+// SolveAssignmentWith is SolveAssignment for values of type W, computing with the operations of ar
+func SolveAssignmentWith[W any](ar Arithmetic[W], matrix [][]W, opts AssignmentOptions) (*WeightedAssignment[W], error) {
+	edges, isLeft, err := assignmentEdges(ar, matrix, opts)
+	if err != nil {
+		return nil, err
+	}
+	result, err := solveAssignment(opts.matching(), ar, edges, isLeft, opts.Minimize, solveOptions{maxCardinality: !opts.Partial})
+	if result == nil {
+		return nil, err
+	}
+	return newAssignment(matrix, result), err
+}
+
+// matching returns the solver configured in opts, or a default one
+func (opts AssignmentOptions) matching() *MaximumWeightedMatching {
+	if opts.Matching != nil {
+		return opts.Matching
+	}
+	return NewMaximumWeightedMatching()
+}
+
+// assignmentEdges validates matrix and returns the edges of its allowed cells, row vertices first
+func assignmentEdges[W any](ar Arithmetic[W], matrix [][]W, opts AssignmentOptions) ([]WeightedEdge[W], []bool, error) {
+	rows, columns := len(matrix), 0
+	if rows > 0 {
+		columns = len(matrix[0])
+	}
+	if opts.Forbidden != nil && len(opts.Forbidden) != rows {
+		return nil, nil, fmt.Errorf("%w: forbidden mask has %d rows, matrix has %d", ErrInvalidMatrix, len(opts.Forbidden), rows)
+	}
+
+	edges := make([]WeightedEdge[W], 0, rows*columns)
+	for r, row := range matrix {
+		if len(row) != columns {
+			return nil, nil, fmt.Errorf("%w: row %d has %d columns, row 0 has %d", ErrInvalidMatrix, r, len(row), columns)
+		}
+		if opts.Forbidden != nil && len(opts.Forbidden[r]) != columns {
+			return nil, nil, fmt.Errorf("%w: forbidden mask row %d has %d columns, matrix has %d", ErrInvalidMatrix, r, len(opts.Forbidden[r]), columns)
+		}
+		for c, value := range row {
+			if opts.Forbidden != nil && opts.Forbidden[r][c] {
+				continue
+			}
+			if !ar.Valid(value) {
+				return nil, nil, fmt.Errorf("%w: cell (%d, %d)", ErrInvalidWeight, r, c)
+			}
+			edges = append(edges, WeightedEdge[W]{Node1: int64(r), Node2: int64(rows + c), Weight: value})
+		}
+	}
+
+	isLeft := make([]bool, rows+columns)
+	for r := 0; r < rows; r++ {
+		isLeft[r] = true
+	}
+	return edges, isLeft, nil
+}
+
+// solveAssignment runs the bipartite solver on the cell edges, negated when minimizing
+func solveAssignment[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], isLeft []bool, minimize bool, opts solveOptions) (result *WeightedMatchingResult[W], err error) {
+	defer recoverFailure(&err)
+
+	solved := edges
+	if minimize {
+		solved = negateEdges(ar, edges)
+	}
+	sol := maxWeightBipartite(mwm, ar, solved, isLeft, opts)
+	return newMatchingResult(ar, edges, sol, opts.maxCardinality), sol.interrupted
+}
+
+// newAssignment translates a matching of the cell edges of matrix into rows and columns
+func newAssignment[W any](matrix [][]W, result *WeightedMatchingResult[W]) *WeightedAssignment[W] {
+	rows, columns := len(matrix), 0
+	if rows > 0 {
+		columns = len(matrix[0])
+	}
+	assignment := &WeightedAssignment[W]{
+		RowToColumn: make([]int, rows),
+		ColumnToRow: make([]int, columns),
+		Total:       result.TotalWeight,
+		Stats:       result.Stats,
+	}
+	for r := range assignment.RowToColumn {
+		assignment.RowToColumn[r] = -1
+	}
+	for c := range assignment.ColumnToRow {
+		assignment.ColumnToRow[c] = -1
+	}
+	for _, pair := range result.Pairs {
+		r, c := int(pair.First), int(pair.Second)-rows
+		assignment.RowToColumn[r] = c
+		assignment.ColumnToRow[c] = r
+	}
+	return assignment
+}
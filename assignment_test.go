@@ -0,0 +1,95 @@
+package mwm
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// TestAssignmentRandom - test random matrices against exhaustive search on the equivalent graph
+func TestAssignmentRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(29))
+	for i := 0; i < 400; i++ {
+		rows, columns := rng.Intn(7), 1+rng.Intn(6)
+		opts := AssignmentOptions{Minimize: i%2 == 1, Partial: i%4 >= 2, Forbidden: make([][]bool, rows)}
+		matrix := make([][]int64, rows)
+		// The graph of the cells, with negated weights when minimizing
+		edges := make([]GraphEdge, 0)
+		for r := range matrix {
+			matrix[r] = make([]int64, columns)
+			opts.Forbidden[r] = make([]bool, columns)
+			for c := range matrix[r] {
+				matrix[r][c] = -30 + rng.Int63n(100)
+				opts.Forbidden[r][c] = rng.Intn(5) == 0
+				if !opts.Forbidden[r][c] {
+					weight := matrix[r][c]
+					if opts.Minimize {
+						weight = -weight
+					}
+					edges = append(edges, GraphEdge{Node1: int64(r), Node2: int64(rows + c), Weight: weight})
+				}
+			}
+		}
+
+		assignment, err := SolveAssignment(matrix, opts)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		total, assigned := int64(0), 0
+		for r, c := range assignment.RowToColumn {
+			if c == -1 {
+				continue
+			}
+			if assignment.ColumnToRow[c] != r || opts.Forbidden[r][c] {
+				t.Fatalf("Iteration %d: invalid assignment of row %d to column %d", i, r, c)
+			}
+			total += matrix[r][c]
+			assigned++
+		}
+		if total != assignment.Total {
+			t.Fatalf("Iteration %d: cells of total %d, reported %d", i, total, assignment.Total)
+		}
+		if opts.Minimize {
+			total = -total
+		}
+		checkBruteForce(t, fmt.Sprintf("Iteration %d, options %+v", i, opts), rows+columns, edges, !opts.Partial, assigned, total)
+	}
+}
+
+// TestAssignmentRectangular - test a wide and a tall cost matrix
+func TestAssignmentRectangular(t *testing.T) {
+	wide := [][]int64{{4, 1, 3}, {2, 6, 5}}
+	assignment, err := SolveAssignment(wide, AssignmentOptions{Minimize: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if assignment.Total != 3 || assignment.RowToColumn[0] != 1 || assignment.RowToColumn[1] != 0 || assignment.ColumnToRow[2] != -1 {
+		t.Errorf("Expected rows 0 and 1 in columns 1 and 0 at cost 3, got %v at cost %d", assignment.RowToColumn, assignment.Total)
+	}
+
+	tall := [][]float64{{1.5}, {2.5}, {0.5}}
+	floatAssignment, err := SolveAssignmentWith(Float64Arithmetic{Epsilon: DefaultEpsilon}, tall, AssignmentOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if floatAssignment.Total != 2.5 || floatAssignment.ColumnToRow[0] != 1 || floatAssignment.RowToColumn[0] != -1 {
+		t.Errorf("Expected row 1 in column 0 with profit 2.5, got %v with profit %v", floatAssignment.RowToColumn, floatAssignment.Total)
+	}
+}
+
+// TestInvalidMatrix - test that ragged matrices and mismatched masks are rejected
+func TestInvalidMatrix(t *testing.T) {
+	for _, tc := range []struct {
+		matrix    [][]int64
+		forbidden [][]bool
+	}{
+		{matrix: [][]int64{{1, 2}, {3}}},
+		{matrix: [][]int64{{1, 2}}, forbidden: [][]bool{{true}}},
+		{matrix: [][]int64{{1, 2}}, forbidden: [][]bool{{true, false}, {false, false}}},
+	} {
+		if _, err := SolveAssignment(tc.matrix, AssignmentOptions{Forbidden: tc.forbidden}); !errors.Is(err, ErrInvalidMatrix) {
+			t.Errorf("Expected ErrInvalidMatrix for %v with mask %v, got %v", tc.matrix, tc.forbidden, err)
+		}
+	}
+}
@@ -0,0 +1,384 @@
+package mwm
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// MaxWeightBipartiteMatching returns the maximum weighted matching of a bipartite graph, computed
+// with successive shortest augmenting paths, the primal-dual form of the Hungarian method, instead
+// of the blossom algorithm. The vertices in left form one side of the bipartition and all other
+// vertices the other side; if left is nil, the bipartition is detected from the edges. An edge
+// within one side, or closing an odd cycle, is reported as an *EdgeError wrapping ErrNotBipartite.
+//
+// Every stage runs Dijkstra's algorithm with a binary heap on reduced costs and augments along the
+// most profitable path, so a run with k augmentations takes O(k m log n), which is O(n^3 log n) on
+// dense graphs and much less on sparse ones. The result has the same weight and cardinality as
+// MaxWeightMatchingE, but ties may be broken differently and it carries no dual solution
+func (mwm *MaximumWeightedMatching) MaxWeightBipartiteMatching(edges []GraphEdge, left []int64, maxCardinality bool) (*MatchingResult, error) {
+	if err := validateEdges(Int64Arithmetic{}, edges); err != nil {
+		return nil, err
+	}
+	isLeft, err := bipartition(edges, left)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := solveOptions{maxCardinality: maxCardinality}
+	return int64Fallback(edges,
+		func() (*MatchingResult, error) {
+			return solveBipartite(mwm, Int64Arithmetic{}, edges, isLeft, opts)
+		},
+		func(edges []WeightedEdge[*big.Int]) (*WeightedMatchingResult[*big.Int], error) {
+			return solveBipartite(mwm, BigIntArithmetic{}, edges, isLeft, opts)
+		})
+}
+
+// MaxWeightBipartiteMatchingWith is MaxWeightBipartiteMatching for weights of type W, computing with the operations of ar
+func MaxWeightBipartiteMatchingWith[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], left []int64, maxCardinality bool) (*WeightedMatchingResult[W], error) {
+	if err := validateEdges(ar, edges); err != nil {
+		return nil, err
+	}
+	isLeft, err := bipartition(edges, left)
+	if err != nil {
+		return nil, err
+	}
+	return solveBipartite(mwm, ar, edges, isLeft, solveOptions{maxCardinality: maxCardinality})
+}
+
+// solveBipartite runs the bipartite solver on validated edges
+func solveBipartite[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], isLeft []bool, opts solveOptions) (result *WeightedMatchingResult[W], err error) {
+	defer recoverFailure(&err)
+
+	sol := maxWeightBipartite(mwm, ar, edges, isLeft, opts)
+	return newMatchingResult(ar, edges, sol, opts.maxCardinality), sol.interrupted
+}
+
+// bipartition returns the side of every vertex of validated edges, taking the vertices in left,
+// or two-coloring the graph if left is nil
+func bipartition[W any](edges []WeightedEdge[W], left []int64) ([]bool, error) {
+	nvertex := int64(0)
+	for _, edge := range edges {
+		nvertex = max(nvertex, edge.Node1+1, edge.Node2+1)
+	}
+
+	if left != nil {
+		isLeft := make([]bool, nvertex)
+		for _, v := range left {
+			if v < 0 {
+				return nil, fmt.Errorf("%w: left vertex %d", ErrNegativeVertex, v)
+			}
+			if v < nvertex {
+				isLeft[v] = true
+			}
+		}
+		for k, edge := range edges {
+			if isLeft[edge.Node1] == isLeft[edge.Node2] {
+				return nil, &EdgeError{Index: k, Node1: edge.Node1, Node2: edge.Node2, Err: ErrNotBipartite}
+			}
+		}
+		return isLeft, nil
+	}
+
+	incident := make([][]int, nvertex)
+	for k, edge := range edges {
+		incident[edge.Node1] = append(incident[edge.Node1], k)
+		incident[edge.Node2] = append(incident[edge.Node2], k)
+	}
+	// color is 1 for the left side, 2 for the right side, and 0 before a vertex is reached
+	color := make([]int8, nvertex)
+	queue := make([]int64, 0)
+	for root := range color {
+		if color[root] != 0 {
+			continue
+		}
+		color[root] = 1
+		queue = append(queue[:0], int64(root))
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			for _, k := range incident[v] {
+				edge := edges[k]
+				w := edge.Node1 + edge.Node2 - v
+				if color[w] == 0 {
+					color[w] = 3 - color[v]
+					queue = append(queue, w)
+				} else if color[w] == color[v] {
+					return nil, &EdgeError{Index: k, Node1: edge.Node1, Node2: edge.Node2, Err: ErrNotBipartite}
+				}
+			}
+		}
+	}
+	isLeft := make([]bool, nvertex)
+	for v, c := range color {
+		isLeft[v] = c == 1
+	}
+	return isLeft, nil
+}
+
+// maxWeightBipartite computes the matching of a bipartite graph by successive shortest paths.
+// Costs are negated weights, and the potentials pi keep the reduced cost pi[i] - pi[j] - w of
+// every edge from a left vertex i to a right vertex j non-negative and zero on matched edges.
+// Free left vertices keep potential zero, and free right vertices lead to a sink of potential
+// sinkpi at reduced cost pi[j] - sinkpi. Every stage runs Dijkstra's algorithm from the free left
+// vertices to the sink and augments along the cheapest path, which leaves the heaviest matching
+// with one more edge
+func maxWeightBipartite[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], isLeft []bool, opts solveOptions) *solution[W] {
+	nvertex := len(isLeft)
+	var stats *SolverStats
+	start := time.Now()
+	if mwm.CollectStats {
+		stats = &SolverStats{}
+	}
+
+	// incident[incidentstart[v]:incidentstart[v+1]] lists the edges of vertex v. The edges of a
+	// right vertex are sorted by decreasing weight, and those before sourcenext[v] lead to matched
+	// left vertices, which never become free again
+	incidentstart := make([]int, nvertex+1)
+	for _, edge := range edges {
+		incidentstart[edge.Node1+1]++
+		incidentstart[edge.Node2+1]++
+	}
+	for v := 0; v < nvertex; v++ {
+		incidentstart[v+1] += incidentstart[v]
+	}
+	incident := make([]int, 2*len(edges))
+	sourcenext := append([]int(nil), incidentstart[:nvertex]...)
+	for k, edge := range edges {
+		incident[sourcenext[edge.Node1]] = k
+		sourcenext[edge.Node1]++
+		incident[sourcenext[edge.Node2]] = k
+		sourcenext[edge.Node2]++
+	}
+	right := make([]int64, 0)
+	for v := 0; v < nvertex; v++ {
+		sourcenext[v] = incidentstart[v]
+		if !isLeft[v] {
+			right = append(right, int64(v))
+			sort.SliceStable(incident[incidentstart[v]:incidentstart[v+1]], func(a, b int) bool {
+				ks := incident[incidentstart[v]:incidentstart[v+1]]
+				return ar.Cmp(edges[ks[a]].Weight, edges[ks[b]].Weight) > 0
+			})
+		}
+	}
+
+	// Right vertices start with the negated largest weight of their edges, which makes all reduced
+	// costs non-negative, and the sink with the smallest of these potentials
+	pi := make([]W, nvertex)
+	initialized := make([]bool, nvertex)
+	for v := range pi {
+		pi[v] = ar.Zero()
+	}
+	sinkpi := ar.Zero()
+	for _, edge := range edges {
+		j := edge.Node1
+		if isLeft[j] {
+			j = edge.Node2
+		}
+		if cost := ar.Sub(ar.Zero(), edge.Weight); !initialized[j] || ar.Cmp(cost, pi[j]) < 0 {
+			pi[j] = cost
+			initialized[j] = true
+		}
+	}
+	for j, ok := range initialized {
+		if ok && ar.Cmp(pi[j], sinkpi) < 0 {
+			sinkpi = pi[j]
+		}
+	}
+
+	sol := &solution[W]{mate: make([]int64, nvertex), mateedge: make([]int, nvertex), stats: stats}
+	for v := 0; v < nvertex; v++ {
+		sol.mate[v] = -1
+		sol.mateedge[v] = -1
+	}
+	mate, mateedge := sol.mate, sol.mateedge
+	total := ar.Zero()
+	if opts.recordCurve {
+		sol.curve = append(sol.curve, total)
+	}
+
+	dist := make([]W, nvertex)
+	reached := make([]bool, nvertex)
+	done := make([]bool, nvertex)
+	// pred is the edge through which a right vertex was reached
+	pred := make([]int, nvertex)
+	// visited lists the right vertices reached in the current stage
+	visited := make([]int64, 0)
+	queue := &distanceHeap[W]{ar: ar}
+	augmentations := 0
+	for {
+		if opts.limitAugmentations && augmentations >= opts.augmentationLimit {
+			break
+		}
+		if opts.ctx != nil {
+			if sol.interrupted = opts.ctx.Err(); sol.interrupted != nil {
+				break
+			}
+		}
+		if stats != nil {
+			stats.Stages++
+		}
+
+		for _, j := range visited {
+			reached[j] = false
+			done[j] = false
+		}
+		visited = visited[:0]
+		queue.items = queue.items[:0]
+		// relax offers the path to the right end of edge k through its left end i at distance d,
+		// and returns the right end if it got closer
+		relax := func(i int64, k int, d W) int64 {
+			edge := &edges[k]
+			j := edge.Node1 + edge.Node2 - i
+			if done[j] {
+				return -1
+			}
+			nd := ar.Add(d, ar.Sub(ar.Sub(pi[i], pi[j]), edge.Weight))
+			if !reached[j] || ar.Cmp(nd, dist[j]) < 0 {
+				if !reached[j] {
+					reached[j] = true
+					visited = append(visited, j)
+				}
+				dist[j] = nd
+				pred[j] = k
+				return j
+			}
+			return -1
+		}
+		// The closest free left vertex of a right vertex is the one with the heaviest edge
+		for _, j := range right {
+			for ; sourcenext[j] < incidentstart[j+1]; sourcenext[j]++ {
+				k := incident[sourcenext[j]]
+				if i := edges[k].Node1 + edges[k].Node2 - j; mate[i] == -1 {
+					relax(i, k, ar.Zero())
+					break
+				}
+			}
+		}
+		for _, j := range visited {
+			queue.push(dist[j], j)
+		}
+
+		// Dijkstra's algorithm over the right vertices, where a matched right vertex continues at
+		// its mate, until no vertex is closer than the sink
+		best := int64(-1)
+		var sinkdist W
+		for len(queue.items) > 0 {
+			d, j := queue.pop()
+			if done[j] {
+				continue
+			}
+			if best != -1 && ar.Cmp(d, sinkdist) >= 0 {
+				break
+			}
+			done[j] = true
+			if mate[j] == -1 {
+				if d := ar.Add(dist[j], ar.Sub(pi[j], sinkpi)); best == -1 || ar.Cmp(d, sinkdist) < 0 {
+					best, sinkdist = j, d
+				}
+				continue
+			}
+			i := mate[j]
+			for _, k := range incident[incidentstart[i]:incidentstart[i+1]] {
+				if k == mateedge[i] {
+					continue
+				}
+				if closer := relax(i, k, dist[j]); closer != -1 {
+					queue.push(dist[closer], closer)
+				}
+			}
+		}
+
+		// The path costs sinkdist + sinkpi; without maximum cardinality mode, a path that does not gain weight ends the algorithm
+		if best == -1 {
+			break
+		}
+		cost := ar.Add(sinkdist, sinkpi)
+		if !opts.maxCardinality && ar.Cmp(cost, ar.Zero()) >= 0 {
+			break
+		}
+
+		// Raise the potentials by the distances, capped at the distance of the sink
+		for _, j := range right {
+			raise := sinkdist
+			if done[j] && ar.Cmp(dist[j], sinkdist) < 0 {
+				raise = dist[j]
+			}
+			pi[j] = ar.Add(pi[j], raise)
+			if i := mate[j]; i != -1 {
+				pi[i] = ar.Add(pi[i], raise)
+			}
+		}
+		sinkpi = ar.Add(sinkpi, sinkdist)
+
+		// Augment along the path back to a free left vertex
+		for j := best; j != -1; {
+			k := pred[j]
+			i := edges[k].Node1 + edges[k].Node2 - j
+			next := mate[i]
+			mate[i], mate[j] = j, i
+			mateedge[i], mateedge[j] = k, k
+			j = next
+		}
+		augmentations++
+		total = ar.Sub(total, cost)
+		if opts.recordCurve {
+			sol.curve = append(sol.curve, total)
+		}
+	}
+
+	if stats != nil {
+		stats.Augmentations = augmentations
+		stats.TotalTime = time.Since(start)
+		stats.ScanTime = stats.TotalTime
+	}
+	return sol
+}
+
+// distanceHeap is a binary min-heap of items, such as right vertices, keyed by tentative distance.
+// An item may be pushed again with another distance; the stale entries are skipped when popped
+type distanceHeap[W any] struct {
+	ar    Arithmetic[W]
+	items []distanceItem[W]
+}
+
+type distanceItem[W any] struct {
+	dist W
+	item int64
+}
+
+func (h *distanceHeap[W]) push(dist W, item int64) {
+	h.items = append(h.items, distanceItem[W]{dist: dist, item: item})
+	for c := len(h.items) - 1; c > 0; {
+		p := (c - 1) / 2
+		if h.ar.Cmp(h.items[c].dist, h.items[p].dist) >= 0 {
+			break
+		}
+		h.items[c], h.items[p] = h.items[p], h.items[c]
+		c = p
+	}
+}
+
+func (h *distanceHeap[W]) pop() (W, int64) {
+	top := h.items[0]
+	last := len(h.items) - 1
+	h.items[0] = h.items[last]
+	h.items = h.items[:last]
+	for p := 0; ; {
+		c := 2*p + 1
+		if c >= last {
+			break
+		}
+		if c+1 < last && h.ar.Cmp(h.items[c+1].dist, h.items[c].dist) < 0 {
+			c++
+		}
+		if h.ar.Cmp(h.items[c].dist, h.items[p].dist) >= 0 {
+			break
+		}
+		h.items[c], h.items[p] = h.items[p], h.items[c]
+		p = c
+	}
+	return top.dist, top.item
+}
@@ -0,0 +1,176 @@
+package mwm
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// randomBipartiteEdges - helper function generating a random bipartite graph with the left
+// vertices 0 .. nleft-1 and the right vertices nleft .. nleft+nright-1, in shuffled order
+func randomBipartiteEdges(rng *rand.Rand, nleft, nright int, density float64, minWeight, maxWeight int64) []GraphEdge {
+	edges := make([]GraphEdge, 0)
+	for i := 0; i < nleft; i++ {
+		for j := nleft; j < nleft+nright; j++ {
+			if rng.Float64() < density {
+				edge := GraphEdge{Node1: int64(i), Node2: int64(j), Weight: minWeight + rng.Int63n(maxWeight-minWeight+1)}
+				if rng.Intn(2) == 0 {
+					edge.Node1, edge.Node2 = edge.Node2, edge.Node1
+				}
+				edges = append(edges, edge)
+			}
+		}
+	}
+	rng.Shuffle(len(edges), func(a, b int) { edges[a], edges[b] = edges[b], edges[a] })
+	return edges
+}
+
+// TestBipartiteRandom - test the bipartite solver against the blossom algorithm
+func TestBipartiteRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(25))
+	matcher := NewMaximumWeightedMatching()
+	for i := 0; i < 400; i++ {
+		nleft, nright := 1+rng.Intn(20), 1+rng.Intn(20)
+		maxCardinality := i%2 == 1
+		edges := randomBipartiteEdges(rng, nleft, nright, 0.05+0.5*rng.Float64(), -20, 50)
+		expected, err := matcher.MaxWeightMatchingE(edges, maxCardinality)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+
+		var left []int64
+		if i%4 < 2 {
+			for v := 0; v < nleft; v++ {
+				left = append(left, int64(v))
+			}
+		}
+		result, err := matcher.MaxWeightBipartiteMatching(edges, left, maxCardinality)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if result.TotalWeight != expected.TotalWeight || (maxCardinality && result.Cardinality != expected.Cardinality) {
+			t.Fatalf("Iteration %d: expected %d edges of weight %d, got %d edges of weight %d\nedges: %v",
+				i, expected.Cardinality, expected.TotalWeight, result.Cardinality, result.TotalWeight, edges)
+		}
+		for p, pair := range result.Pairs {
+			if result.Mate[pair.First] != pair.Second || result.Mate[pair.Second] != pair.First {
+				t.Fatalf("Iteration %d: inconsistent pair %v", i, pair)
+			}
+			if edge := edges[result.EdgeIndices[p]]; edge.Node1+edge.Node2 != pair.First+pair.Second {
+				t.Fatalf("Iteration %d: pair %v does not match edge %v", i, pair, edge)
+			}
+		}
+	}
+}
+
+// TestDetectBipartite - test that DetectBipartite leaves the results of all entry points unchanged
+func TestDetectBipartite(t *testing.T) {
+	rng := rand.New(rand.NewSource(26))
+	matcher := NewMaximumWeightedMatching()
+	detecting := NewMaximumWeightedMatching()
+	detecting.DetectBipartite = true
+	for i := 0; i < 100; i++ {
+		edges := randomBipartiteEdges(rng, 1+rng.Intn(15), 1+rng.Intn(15), 0.3, -20, 50)
+		expected, err := MinWeightMatchingWith(matcher, Int64Arithmetic{}, edges, ExactCardinality, 3)
+		result, detectedErr := MinWeightMatchingWith(detecting, Int64Arithmetic{}, edges, ExactCardinality, 3)
+		if !errors.Is(detectedErr, ErrCardinalityUnreachable) && detectedErr != err {
+			t.Fatalf("Iteration %d: expected error %v, got %v", i, err, detectedErr)
+		}
+		if result.TotalWeight != expected.TotalWeight || result.Cardinality != expected.Cardinality {
+			t.Fatalf("Iteration %d: expected %d edges of weight %d, got %d edges of weight %d",
+				i, expected.Cardinality, expected.TotalWeight, result.Cardinality, result.TotalWeight)
+		}
+		if result.VertexDuals != nil {
+			t.Fatalf("Iteration %d: expected the bipartite solver, got vertex duals", i)
+		}
+
+		curve, err := detecting.MaxWeightCurve(edges)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		expectedCurve, err := matcher.MaxWeightCurve(edges)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		for j := range expectedCurve {
+			if len(curve) != len(expectedCurve) || curve[j] != expectedCurve[j] {
+				t.Fatalf("Iteration %d: expected curve %v, got %v", i, expectedCurve, curve)
+			}
+		}
+	}
+
+	triangle := []GraphEdge{{Node1: 0, Node2: 1, Weight: 2}, {Node1: 1, Node2: 2, Weight: 3}, {Node1: 0, Node2: 2, Weight: 4}}
+	result, err := detecting.MaxWeightMatchingE(triangle, false)
+	if err != nil || result.TotalWeight != 4 || result.VertexDuals == nil {
+		t.Errorf("Expected the blossom algorithm to match edge 0 -- 2, got %v with error %v", result, err)
+	}
+}
+
+// TestBipartiteFloat - test the generic entry point with floating-point weights
+func TestBipartiteFloat(t *testing.T) {
+	rng := rand.New(rand.NewSource(27))
+	matcher := NewMaximumWeightedMatching()
+	ar := Float64Arithmetic{Epsilon: DefaultEpsilon}
+	for i := 0; i < 100; i++ {
+		edges := make([]FloatGraphEdge, 0)
+		for _, edge := range randomBipartiteEdges(rng, 1+rng.Intn(12), 1+rng.Intn(12), 0.4, 0, 1000) {
+			edges = append(edges, FloatGraphEdge{Node1: edge.Node1, Node2: edge.Node2, Weight: float64(edge.Weight) / 7})
+		}
+		expected, err := MaxWeightMatchingWith(matcher, ar, edges, false)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		result, err := MaxWeightBipartiteMatchingWith(matcher, ar, edges, nil, false)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if ar.Cmp(result.TotalWeight, expected.TotalWeight) != 0 {
+			t.Fatalf("Iteration %d: expected weight %v, got %v", i, expected.TotalWeight, result.TotalWeight)
+		}
+	}
+}
+
+// TestNotBipartite - test that edges violating the bipartition are reported
+func TestNotBipartite(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	triangle := []GraphEdge{{Node1: 0, Node2: 1, Weight: 2}, {Node1: 1, Node2: 2, Weight: 3}, {Node1: 0, Node2: 2, Weight: 4}}
+	var edgeErr *EdgeError
+	if _, err := matcher.MaxWeightBipartiteMatching(triangle, nil, false); !errors.As(err, &edgeErr) || !errors.Is(err, ErrNotBipartite) {
+		t.Errorf("Expected an EdgeError wrapping ErrNotBipartite, got %v", err)
+	}
+	path := triangle[:2]
+	if _, err := matcher.MaxWeightBipartiteMatching(path, []int64{0, 1}, false); !errors.As(err, &edgeErr) || edgeErr.Index != 0 {
+		t.Errorf("Expected an EdgeError for edge 0, got %v", err)
+	}
+	if _, err := matcher.MaxWeightBipartiteMatching(path, []int64{-1}, false); !errors.Is(err, ErrNegativeVertex) {
+		t.Errorf("Expected ErrNegativeVertex, got %v", err)
+	}
+	result, err := matcher.MaxWeightBipartiteMatching(path, []int64{1}, false)
+	if err != nil || result.TotalWeight != 3 {
+		t.Errorf("Expected edge 1 -- 2 of weight 3, got %v with error %v", result, err)
+	}
+}
+
+// BenchmarkBipartite - benchmark the bipartite solver on a workers-vs-tasks graph
+func BenchmarkBipartite(b *testing.B) {
+	rng := rand.New(rand.NewSource(28))
+	edges := randomBipartiteEdges(rng, 200, 200, 0.2, 1, 1000)
+	matcher := NewMaximumWeightedMatching()
+	for b.Loop() {
+		if _, err := matcher.MaxWeightBipartiteMatching(edges, nil, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkBipartiteBlossom - benchmark the blossom algorithm on the graph of BenchmarkBipartite
+func BenchmarkBipartiteBlossom(b *testing.B) {
+	rng := rand.New(rand.NewSource(28))
+	edges := randomBipartiteEdges(rng, 200, 200, 0.2, 1, 1000)
+	matcher := NewMaximumWeightedMatching()
+	for b.Loop() {
+		if _, err := matcher.MaxWeightMatchingE(edges, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
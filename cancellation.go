@@ -0,0 +1,22 @@
+package mwm
+
+import "context"
+
+// MaxWeightMatchingContext is MaxWeightMatchingE with cancellation. The context is checked between
+// the stages and substages of the algorithm; once it is done, the matching found so far is returned
+// together with ctx.Err(). That matching is valid, because the algorithm only changes it by complete
+// augmentations, but it is not necessarily optimal and its VertexDuals and Blossoms are nil
+func (mwm *MaximumWeightedMatching) MaxWeightMatchingContext(ctx context.Context, edges []GraphEdge, maxCardinality bool) (*MatchingResult, error) {
+	if err := validateEdges(Int64Arithmetic{}, edges); err != nil {
+		return nil, err
+	}
+	return mwm.solveInt64(edges, solveOptions{maxCardinality: maxCardinality, ctx: ctx})
+}
+
+// MaxWeightMatchingWithContext is MaxWeightMatchingWith with cancellation like MaxWeightMatchingContext
+func MaxWeightMatchingWithContext[W any](ctx context.Context, mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], maxCardinality bool) (*WeightedMatchingResult[W], error) {
+	if err := validateEdges(ar, edges); err != nil {
+		return nil, err
+	}
+	return solveWith(mwm, ar, edges, solveOptions{maxCardinality: maxCardinality, ctx: ctx})
+}
@@ -0,0 +1,69 @@
+package mwm
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// countdownContext - helper context that is cancelled after a fixed number of Err calls
+type countdownContext struct {
+	context.Context
+	remaining int
+}
+
+func (c *countdownContext) Err() error {
+	if c.remaining == 0 {
+		return context.Canceled
+	}
+	c.remaining--
+	return nil
+}
+
+// TestMaxWeightMatchingContext - test that an uncancelled context gives the usual result
+func TestMaxWeightMatchingContext(t *testing.T) {
+	rng := rand.New(rand.NewSource(10))
+	matcher := NewMaximumWeightedMatching()
+	edges := randomEdges(rng, 30, 0.3, -10, 100)
+	result, err := matcher.MaxWeightMatchingContext(context.Background(), edges, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := matcher.MaxWeightMatchingResult(edges, false)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected.Pairs, result.Pairs)
+	}
+}
+
+// TestMaxWeightMatchingContextCancelled - test that a cancelled context stops the solver with a valid partial matching
+func TestMaxWeightMatchingContextCancelled(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	matcher := NewMaximumWeightedMatching()
+	edges := randomEdges(rng, 40, 0.3, 1, 100)
+	full := matcher.MaxWeightMatchingResult(edges, false)
+
+	for checks := 0; checks < 60; checks += 5 {
+		ctx := &countdownContext{Context: context.Background(), remaining: checks}
+		result, err := matcher.MaxWeightMatchingContext(ctx, edges, false)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("After %d checks: expected context.Canceled, got %v", checks, err)
+		}
+		if result.VertexDuals != nil || result.Blossoms != nil {
+			t.Errorf("After %d checks: expected no dual solution", checks)
+		}
+		if result.Cardinality > full.Cardinality {
+			t.Errorf("After %d checks: partial matching has %d edges, optimum has %d", checks, result.Cardinality, full.Cardinality)
+		}
+		for i, pair := range result.Pairs {
+			edge := edges[result.EdgeIndices[i]]
+			if edge.Node1 != pair.First && edge.Node2 != pair.First {
+				t.Fatalf("After %d checks: pair %v is not edge %v", checks, pair, edge)
+			}
+			if result.Mate[pair.First] != pair.Second || result.Mate[pair.Second] != pair.First {
+				t.Fatalf("After %d checks: inconsistent mate for pair %v", checks, pair)
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package mwm
+
+import "math/big"
+
+// MaxWeightMatchingCardinality returns the matching of maximum total weight among the matchings
+// selected by objective, like MinWeightMatching does for the minimum. For ExactCardinality the
+// algorithm runs in maximum cardinality mode and stops after k augmentations, and if the graph has
+// no matching with k edges, the maximum weight matching of maximum cardinality is returned
+// together with ErrCardinalityUnreachable. For AtMostCardinality it stops after k augmentations
+// or when no augmentation gains weight, whichever comes first.
+//
+// Every augmentation adds one edge, and while all free vertices share the same dual the matching
+// is the heaviest of its size, so VertexDuals and Blossoms certify optimality for the selected
+// number of edges
+func (mwm *MaximumWeightedMatching) MaxWeightMatchingCardinality(edges []GraphEdge, objective CardinalityObjective, k int) (*MatchingResult, error) {
+	if err := validateEdges(Int64Arithmetic{}, edges); err != nil {
+		return nil, err
+	}
+	opts, err := objectiveOptions(objective, k)
+	if err != nil {
+		return nil, err
+	}
+
+	return int64Fallback(edges,
+		func() (*MatchingResult, error) {
+			return maxWeightCardinality(mwm, Int64Arithmetic{}, edges, opts)
+		},
+		func(edges []WeightedEdge[*big.Int]) (*WeightedMatchingResult[*big.Int], error) {
+			return maxWeightCardinality(mwm, BigIntArithmetic{}, edges, opts)
+		})
+}
+
+// MaxWeightMatchingCardinalityWith is MaxWeightMatchingCardinality for weights of type W, computing with the operations of ar
+func MaxWeightMatchingCardinalityWith[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], objective CardinalityObjective, k int) (*WeightedMatchingResult[W], error) {
+	if err := validateEdges(ar, edges); err != nil {
+		return nil, err
+	}
+	opts, err := objectiveOptions(objective, k)
+	if err != nil {
+		return nil, err
+	}
+	return maxWeightCardinality(mwm, ar, edges, opts)
+}
+
+// maxWeightCardinality solves the problem selected by opts on validated edges
+func maxWeightCardinality[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], opts solveOptions) (*WeightedMatchingResult[W], error) {
+	matching, err := solveWith(mwm, ar, edges, opts)
+	if err != nil {
+		return matching, err
+	}
+	return matching, checkCardinality(opts, matching.Cardinality)
+}
+
+// MaxWeightCurve returns the weight-vs-cardinality curve of the graph: element j is the largest
+// total weight of a matching with exactly j edges, from the empty matching up to the maximum
+// cardinality. The curve is concave, and it costs a single run in maximum cardinality mode,
+// which records the weight after every augmentation.
+//
+// Weights beyond SafeWeightLimit are handled with big integer arithmetic; a point of the curve
+// that does not fit in int64 is reported as ErrWeightOverflow
+func (mwm *MaximumWeightedMatching) MaxWeightCurve(edges []GraphEdge) ([]int64, error) {
+	if err := validateEdges(Int64Arithmetic{}, edges); err != nil {
+		return nil, err
+	}
+	if withinSafeWeightLimit(edges) {
+		curve, err := weightCurve(mwm, Int64Arithmetic{}, edges)
+		if err != ErrWeightOverflow {
+			return curve, err
+		}
+	}
+
+	bigCurve, err := weightCurve(mwm, BigIntArithmetic{}, bigEdges(edges))
+	if err != nil {
+		return nil, err
+	}
+	curve := make([]int64, len(bigCurve))
+	for j, w := range bigCurve {
+		if !w.IsInt64() {
+			return nil, ErrWeightOverflow
+		}
+		curve[j] = w.Int64()
+	}
+	return curve, nil
+}
+
+// MaxWeightCurveWith is MaxWeightCurve for weights of type W, computing with the operations of ar
+func MaxWeightCurveWith[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W]) ([]W, error) {
+	if err := validateEdges(ar, edges); err != nil {
+		return nil, err
+	}
+	return weightCurve(mwm, ar, edges)
+}
+
+// weightCurve runs the algorithm in maximum cardinality mode on validated edges and returns the recorded curve
+func weightCurve[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W]) (curve []W, err error) {
+	defer recoverFailure(&err)
+
+	sol := maxWeightMatchingInternal(mwm, ar, edges, solveOptions{maxCardinality: true, recordCurve: true}, nil)
+	return sol.curve, nil
+}
@@ -0,0 +1,99 @@
+package mwm
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// bruteForceCurve - helper function computing the best weight of a matching with exactly j edges
+// for every j up to the maximum cardinality, by enumerating every matching among a few edges
+func bruteForceCurve(edges []GraphEdge) []int64 {
+	curve := []int64{0}
+	forEachMatching(edges, func(_ int, _ map[int64]bool, cardinality int, weight int64) {
+		if cardinality == len(curve) {
+			curve = append(curve, weight)
+		} else if cardinality < len(curve) && weight > curve[cardinality] {
+			curve[cardinality] = weight
+		}
+	})
+	return curve
+}
+
+// TestCardinalityRandom - test exactly-k, at-most-k and the weight curve against exhaustive search
+func TestCardinalityRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(24))
+	matcher := NewMaximumWeightedMatching()
+	for i := 0; i < 300; i++ {
+		edges := randomEdges(rng, 2+rng.Intn(7), 0.5, -20, 50)
+		if len(edges) > 14 {
+			edges = edges[:14]
+		}
+		expected := bruteForceCurve(edges)
+
+		curve, err := matcher.MaxWeightCurve(edges)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if len(curve) != len(expected) {
+			t.Fatalf("Iteration %d: expected curve %v, got %v\nedges: %v", i, expected, curve, edges)
+		}
+		best := int64(0)
+		for k := range expected {
+			if curve[k] != expected[k] {
+				t.Fatalf("Iteration %d: expected curve %v, got %v\nedges: %v", i, expected, curve, edges)
+			}
+			exact, err := matcher.MaxWeightMatchingCardinality(edges, ExactCardinality, k)
+			if err != nil {
+				t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+			}
+			checkOptimum(t, fmt.Sprintf("Iteration %d, exactly %d edges", i, k), edges, true, k, expected[k], exact.Cardinality, exact.TotalWeight)
+			best = max(best, expected[k])
+			atMost, err := matcher.MaxWeightMatchingCardinality(edges, AtMostCardinality, k)
+			if err != nil {
+				t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+			}
+			if atMost.Cardinality > k || atMost.TotalWeight != best {
+				t.Fatalf("Iteration %d: expected at most %d edges of weight %d, got %d edges of weight %d", i, k, best, atMost.Cardinality, atMost.TotalWeight)
+			}
+		}
+
+		k := len(expected)
+		result, err := matcher.MaxWeightMatchingCardinality(edges, ExactCardinality, k)
+		if !errors.Is(err, ErrCardinalityUnreachable) || result.Cardinality != k-1 {
+			t.Fatalf("Iteration %d: expected ErrCardinalityUnreachable with %d edges, got %v", i, k-1, err)
+		}
+	}
+}
+
+// TestMinWeightAtMost - test the at-most-k objective of MinWeightMatching
+func TestMinWeightAtMost(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{{Node1: 0, Node2: 1, Weight: -5}, {Node1: 2, Node2: 3, Weight: -3}, {Node1: 4, Node2: 5, Weight: 2}}
+	for k, expected := range []int64{0, -5, -8, -8} {
+		result, err := matcher.MinWeightMatching(edges, AtMostCardinality, k)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.TotalWeight != expected {
+			t.Errorf("Expected weight %d for at most %d edges, got %d", expected, k, result.TotalWeight)
+		}
+	}
+	if _, err := matcher.MaxWeightMatchingCardinality(edges, AtMostCardinality, -1); !errors.Is(err, ErrCardinalityUnreachable) {
+		t.Errorf("Expected ErrCardinalityUnreachable, got %v", err)
+	}
+}
+
+// TestMaxWeightCurveLarge - test that a curve beyond SafeWeightLimit is computed with big integers
+func TestMaxWeightCurveLarge(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{{Node1: 0, Node2: 1, Weight: SafeWeightLimit + 1}, {Node1: 1, Node2: 2, Weight: 3}, {Node1: 2, Node2: 3, Weight: 1}}
+	curve, err := matcher.MaxWeightCurve(edges)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(curve) != 3 || curve[1] != SafeWeightLimit+1 || curve[2] != SafeWeightLimit+2 {
+		t.Errorf("Expected curve [0 %d %d], got %v", int64(SafeWeightLimit+1), int64(SafeWeightLimit+2), curve)
+	}
+}
@@ -0,0 +1,128 @@
+package mwm
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// EdgeConstraints restricts the matchings considered by MaxWeightMatchingConstrained.
+// Edges are identified by their index in the input slice
+type EdgeConstraints struct {
+	// Forced lists edges that must be matched
+	Forced []int
+	// Forbidden lists edges that must not be matched
+	Forbidden []int
+}
+
+// MaxWeightMatchingConstrained returns the maximum weighted matching among the matchings that
+// contain all forced edges and no forbidden edge. In maximum cardinality mode the matching has
+// the largest number of edges possible under the constraints. Forced edges that share a vertex,
+// edges that are both forced and forbidden, and indices outside edges are reported with
+// ErrInfeasibleConstraints.
+//
+// The weights are never changed: the forced edges and their vertices are removed from the graph
+// together with the forbidden edges, and the rest is solved as usual. TotalWeight includes the
+// forced edges. VertexDuals and Blossoms are nil, since the dual solution of the remaining graph
+// is no certificate for the constrained problem
+func (mwm *MaximumWeightedMatching) MaxWeightMatchingConstrained(edges []GraphEdge, maxCardinality bool, constraints EdgeConstraints) (*MatchingResult, error) {
+	if err := validateEdges(Int64Arithmetic{}, edges); err != nil {
+		return nil, err
+	}
+	if err := checkConstraints(edges, constraints); err != nil {
+		return nil, err
+	}
+
+	opts := solveOptions{maxCardinality: maxCardinality}
+	return int64Fallback(edges,
+		func() (*MatchingResult, error) {
+			return solveConstrained(mwm, Int64Arithmetic{}, edges, opts, constraints)
+		},
+		func(edges []WeightedEdge[*big.Int]) (*WeightedMatchingResult[*big.Int], error) {
+			return solveConstrained(mwm, BigIntArithmetic{}, edges, opts, constraints)
+		})
+}
+
+// MaxWeightMatchingConstrainedWith is MaxWeightMatchingConstrained for weights of type W, computing with the operations of ar
+func MaxWeightMatchingConstrainedWith[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], maxCardinality bool, constraints EdgeConstraints) (*WeightedMatchingResult[W], error) {
+	if err := validateEdges(ar, edges); err != nil {
+		return nil, err
+	}
+	if err := checkConstraints(edges, constraints); err != nil {
+		return nil, err
+	}
+	return solveConstrained(mwm, ar, edges, solveOptions{maxCardinality: maxCardinality}, constraints)
+}
+
+// checkConstraints reports constraints that no matching of edges can satisfy
+func checkConstraints[W any](edges []WeightedEdge[W], constraints EdgeConstraints) error {
+	forbidden := make(map[int]bool, len(constraints.Forbidden))
+	for _, k := range constraints.Forbidden {
+		if k < 0 || k >= len(edges) {
+			return fmt.Errorf("%w: forbidden edge %d does not exist", ErrInfeasibleConstraints, k)
+		}
+		forbidden[k] = true
+	}
+
+	// forcedAt holds the forced edge of every vertex covered so far
+	forcedAt := make(map[int64]int, 2*len(constraints.Forced))
+	for _, k := range constraints.Forced {
+		if k < 0 || k >= len(edges) {
+			return fmt.Errorf("%w: forced edge %d does not exist", ErrInfeasibleConstraints, k)
+		}
+		if forbidden[k] {
+			return fmt.Errorf("%w: edge %d is both forced and forbidden", ErrInfeasibleConstraints, k)
+		}
+		for _, v := range []int64{edges[k].Node1, edges[k].Node2} {
+			if other, ok := forcedAt[v]; ok && other != k {
+				return fmt.Errorf("%w: forced edges %d and %d share vertex %d", ErrInfeasibleConstraints, other, k, v)
+			}
+			forcedAt[v] = k
+		}
+	}
+	return nil
+}
+
+// solveConstrained matches the forced edges of validated edges and solves the remaining graph
+func solveConstrained[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], opts solveOptions, constraints EdgeConstraints) (result *WeightedMatchingResult[W], err error) {
+	defer recoverFailure(&err)
+
+	nvertex := 0
+	for _, edge := range edges {
+		nvertex = max(nvertex, int(edge.Node1)+1, int(edge.Node2)+1)
+	}
+	combined := &solution[W]{mate: make([]int64, nvertex), mateedge: make([]int, nvertex)}
+	for v := range combined.mate {
+		combined.mate[v] = -1
+		combined.mateedge[v] = -1
+	}
+	for _, k := range constraints.Forced {
+		edge := edges[k]
+		combined.mate[edge.Node1], combined.mate[edge.Node2] = edge.Node2, edge.Node1
+		combined.mateedge[edge.Node1], combined.mateedge[edge.Node2] = k, k
+	}
+
+	// The remaining graph keeps the vertex numbers; original maps its edges back to the input
+	excluded := make(map[int]bool, len(constraints.Forbidden))
+	for _, k := range constraints.Forbidden {
+		excluded[k] = true
+	}
+	remaining := make([]WeightedEdge[W], 0, len(edges))
+	original := make([]int, 0, len(edges))
+	for k, edge := range edges {
+		if !excluded[k] && combined.mate[edge.Node1] == -1 && combined.mate[edge.Node2] == -1 {
+			remaining = append(remaining, edge)
+			original = append(original, k)
+		}
+	}
+
+	sol := maxWeightMatchingInternal(mwm, ar, remaining, opts, nil)
+	for v, w := range sol.mate {
+		if w != -1 {
+			combined.mate[v] = w
+			combined.mateedge[v] = original[sol.mateedge[v]]
+		}
+	}
+	combined.interrupted = sol.interrupted
+	combined.stats = sol.stats
+	return newMatchingResult(ar, edges, combined, opts.maxCardinality), sol.interrupted
+}
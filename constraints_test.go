@@ -0,0 +1,113 @@
+package mwm
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// bruteForceConstrained - helper function enumerating every matching among a few edges.
+// Returns whether a matching satisfies the constraints, and the cardinality and weight of the best one
+func bruteForceConstrained(edges []GraphEdge, maxCardinality bool, constraints EdgeConstraints) (bool, int, int64) {
+	found, bestCardinality, bestWeight := false, 0, int64(0)
+	forEachMatching(edges, func(subset int, _ map[int64]bool, cardinality int, weight int64) {
+		for _, k := range constraints.Forced {
+			if subset&(1<<k) == 0 {
+				return
+			}
+		}
+		for _, k := range constraints.Forbidden {
+			if subset&(1<<k) != 0 {
+				return
+			}
+		}
+		better := weight > bestWeight
+		if maxCardinality && cardinality != bestCardinality {
+			better = cardinality > bestCardinality
+		}
+		if !found || better {
+			found, bestCardinality, bestWeight = true, cardinality, weight
+		}
+	})
+	return found, bestCardinality, bestWeight
+}
+
+// TestConstrainedRandom - test random constraints against exhaustive search
+func TestConstrainedRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(22))
+	matcher := NewMaximumWeightedMatching()
+	for i := 0; i < 400; i++ {
+		maxCardinality := i%2 == 1
+		edges := randomEdges(rng, 2+rng.Intn(6), 0.6, -20, 50)
+		if len(edges) > 14 {
+			edges = edges[:14]
+		}
+		constraints := EdgeConstraints{}
+		for k := range edges {
+			switch rng.Intn(6) {
+			case 0:
+				constraints.Forced = append(constraints.Forced, k)
+			case 1:
+				constraints.Forbidden = append(constraints.Forbidden, k)
+			}
+		}
+
+		feasible, cardinality, weight := bruteForceConstrained(edges, maxCardinality, constraints)
+		result, err := matcher.MaxWeightMatchingConstrained(edges, maxCardinality, constraints)
+		if !feasible {
+			if !errors.Is(err, ErrInfeasibleConstraints) {
+				t.Fatalf("Iteration %d: expected ErrInfeasibleConstraints, got %v", i, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		checkOptimum(t, fmt.Sprintf("Iteration %d, constraints %v", i, constraints), edges, maxCardinality, cardinality, weight, result.Cardinality, result.TotalWeight)
+		for _, k := range constraints.Forced {
+			if result.Mate[edges[k].Node1] != edges[k].Node2 {
+				t.Fatalf("Iteration %d: forced edge %d is not matched", i, k)
+			}
+		}
+		for _, k := range result.EdgeIndices {
+			for _, f := range constraints.Forbidden {
+				if k == f {
+					t.Fatalf("Iteration %d: forbidden edge %d is matched", i, k)
+				}
+			}
+		}
+	}
+}
+
+// TestConstrainedTrueWeights - test that a forced edge of negative weight counts with its own weight
+func TestConstrainedTrueWeights(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{{Node1: 0, Node2: 1, Weight: -4}, {Node1: 1, Node2: 2, Weight: 10}, {Node1: 2, Node2: 3, Weight: 6}}
+	result, err := matcher.MaxWeightMatchingConstrained(edges, false, EdgeConstraints{Forced: []int{0}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.TotalWeight != 2 || result.Cardinality != 2 || result.Weights[0] != -4 {
+		t.Errorf("Expected edges 0 -- 1 and 2 -- 3 of weight 2, got %v of weight %d", result.Pairs, result.TotalWeight)
+	}
+	if result.VertexDuals != nil || result.Blossoms != nil {
+		t.Errorf("Expected no dual solution, got %v and %v", result.VertexDuals, result.Blossoms)
+	}
+}
+
+// TestInfeasibleConstraints - test that contradicting constraints are rejected
+func TestInfeasibleConstraints(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{{Node1: 0, Node2: 1, Weight: 1}, {Node1: 1, Node2: 2, Weight: 1}, {Node1: 2, Node2: 3, Weight: 1}}
+	for _, constraints := range []EdgeConstraints{
+		{Forced: []int{0, 1}},
+		{Forced: []int{2}, Forbidden: []int{2}},
+		{Forced: []int{3}},
+		{Forbidden: []int{-1}},
+	} {
+		if _, err := matcher.MaxWeightMatchingConstrained(edges, false, constraints); !errors.Is(err, ErrInfeasibleConstraints) {
+			t.Errorf("Expected ErrInfeasibleConstraints for %v, got %v", constraints, err)
+		}
+	}
+}
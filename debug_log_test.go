@@ -0,0 +1,62 @@
+package mwm
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+// TestLoggerEvents - test that the algorithm steps are logged as structured events
+func TestLoggerEvents(t *testing.T) {
+	var buf bytes.Buffer
+	matcher := NewMaximumWeightedMatching()
+	matcher.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	edges := []GraphEdge{
+		{Node1: 1, Node2: 2, Weight: 9},
+		{Node1: 1, Node2: 3, Weight: 8},
+		{Node1: 2, Node2: 3, Weight: 10},
+		{Node1: 1, Node2: 4, Weight: 5},
+		{Node1: 4, Node2: 5, Weight: 4},
+		{Node1: 1, Node2: 6, Weight: 3},
+	}
+	result := matcher.MaxWeightMatchingResult(edges, false)
+
+	counts := make(map[string]int)
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var event map[string]any
+		if err := decoder.Decode(&event); err != nil {
+			t.Fatalf("Invalid log line: %v", err)
+		}
+		msg, _ := event["msg"].(string)
+		counts[msg]++
+		if _, ok := event["stage"]; !ok {
+			t.Errorf("Event %q has no stage attribute", msg)
+		}
+		if msg == "dual update" {
+			if _, ok := event["deltatype"]; !ok {
+				t.Errorf("Dual update without deltatype: %v", event)
+			}
+		}
+	}
+	if counts["augment matching"] != result.Cardinality {
+		t.Errorf("Expected %d augmentations, got %d", result.Cardinality, counts["augment matching"])
+	}
+	for _, msg := range []string{"stage", "assign label", "dual update", "matching"} {
+		if counts[msg] == 0 {
+			t.Errorf("Expected %q events, got none", msg)
+		}
+	}
+}
+
+// TestLoggerLevel - test that nothing is logged when debug level is disabled
+func TestLoggerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	matcher := NewMaximumWeightedMatching()
+	matcher.Logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	matcher.MaxWeightMatching([]GraphEdge{{Node1: 0, Node2: 1, Weight: 1}}, false)
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output, got %q", buf.String())
+	}
+}
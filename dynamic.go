@@ -0,0 +1,597 @@
+package mwm
+
+import "fmt"
+
+// DynamicMatching maintains a maximum weighted matching of a graph under a stream of edits.
+// It keeps the matching, the blossoms and the duals of the last run between edits. Edits that
+// keep this dual solution feasible, such as removing or lowering an unmatched edge outside any
+// blossom, leave the matching as it is. Any other edit dissolves only the blossoms it breaks and
+// frees the vertices whose matched edge or dual it changes, and the next run starts from them as
+// roots, like a warm start. With PriorityQueueEngine an edit then costs the few stages that repair
+// it, plus O(n) to reset the labels; the scan engine spends O(n) on every substage. The initial
+// solve and all re-optimizations run on the Engine of Matching, so large graphs should use
+// PriorityQueueEngine.
+//
+// Vertices are numbered like in the edges and exist as soon as an edge refers to them.
+// There is at most one edge between two vertices. A DynamicMatching must not be used by
+// several goroutines at once
+type DynamicMatching[W any] struct {
+	// Matching holds the options of the algorithm
+	Matching *MaximumWeightedMatching
+	// Arithmetic computes with the weights
+	Arithmetic Arithmetic[W]
+
+	maxCardinality bool
+	// nvertex is the number of vertices; the engine may hold more, which stay isolated
+	nvertex int
+	// index holds the position in the edges of the engine of the edge between a pair of vertices,
+	// smaller vertex first
+	index map[Pair]int
+	// certified reports whether the engine holds an optimal matching with its dual solution,
+	// which fails after a solver error
+	certified bool
+	// engine holds the graph in adjacency lists and the matching, blossoms and duals of the last run
+	engine engine[W]
+}
+
+// NewDynamicMatching creates a dynamic matching of the graph given by edges and solves it
+func NewDynamicMatching[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], maxCardinality bool) (*DynamicMatching[W], error) {
+	if err := validateEdges(ar, edges); err != nil {
+		return nil, err
+	}
+	dm := &DynamicMatching[W]{
+		Matching:       mwm,
+		Arithmetic:     ar,
+		maxCardinality: maxCardinality,
+		index:          make(map[Pair]int, len(edges)),
+	}
+	e := &dm.engine
+	e.edges = make([]WeightedEdge[W], 0, len(edges))
+	for k, edge := range edges {
+		key := pairKey(edge.Node1, edge.Node2)
+		if _, ok := dm.index[key]; ok {
+			return nil, &EdgeError{Index: k, Node1: edge.Node1, Node2: edge.Node2, Err: ErrDuplicateEdge}
+		}
+		dm.index[key] = len(e.edges)
+		e.edges = append(e.edges, edge)
+		dm.nvertex = max(dm.nvertex, int(max(edge.Node1, edge.Node2))+1)
+	}
+	if err := dm.update(nil, false); err != nil {
+		return nil, err
+	}
+	return dm, nil
+}
+
+// pairKey returns the key of the edge between u and v in DynamicMatching.index
+func pairKey(u, v int64) Pair {
+	if u > v {
+		u, v = v, u
+	}
+	return Pair{First: u, Second: v}
+}
+
+// grow extends the vertices to n with free vertices. The engine grows by at least half its
+// size at once, because growing renumbers its blossoms
+func (dm *DynamicMatching[W]) grow(n int) {
+	if n <= dm.nvertex {
+		return
+	}
+	dm.nvertex = n
+	if e := &dm.engine; n > e.nvertex {
+		e.growVertices(max(n, e.nvertex+e.nvertex/2))
+	}
+}
+
+// update applies edit to the engine and re-optimizes. A certified matching is re-optimized from
+// the repaired matching, blossoms and duals if the edit freed a vertex above the floor, or in
+// maximum cardinality mode if it freed any vertex or force is set. Otherwise the graph is solved
+// from scratch. If the solver fails, the matching is cleared, so that it stays valid for the edited graph
+func (dm *DynamicMatching[W]) update(edit func(e *engine[W]), force bool) (err error) {
+	defer func() {
+		if err != nil {
+			dm.clear()
+		}
+	}()
+	defer recoverFailure(&err)
+
+	e := &dm.engine
+	if edit != nil {
+		e.beginEdit(dm.Matching)
+		edit(e)
+	}
+	if !dm.certified {
+		e.init(dm.Matching, dm.Arithmetic, e.edges, solveOptions{maxCardinality: dm.maxCardinality})
+		e.run()
+		e.startDynamic(dm.nvertex)
+		dm.certified = true
+		return nil
+	}
+	freed := len(e.freed) > 0
+	if e.settleFreed() || (dm.maxCardinality && (freed || force)) {
+		dm.certified = false
+		e.resume()
+		dm.certified = true
+	}
+	return nil
+}
+
+// clear drops the matching after a solver error and keeps the graph
+func (dm *DynamicMatching[W]) clear() {
+	e := &dm.engine
+	e.init(dm.Matching, dm.Arithmetic, e.edges, solveOptions{maxCardinality: dm.maxCardinality})
+	e.startDynamic(dm.nvertex)
+	dm.certified = false
+}
+
+// find returns the position of the edge between u and v, or an error wrapping ErrUnknownEdge
+func (dm *DynamicMatching[W]) find(u, v int64) (int, error) {
+	k, ok := dm.index[pairKey(u, v)]
+	if !ok {
+		return 0, fmt.Errorf("%w: %d -- %d", ErrUnknownEdge, u, v)
+	}
+	return k, nil
+}
+
+// AddVertex adds an isolated vertex and returns its number
+func (dm *DynamicMatching[W]) AddVertex() int64 {
+	v := dm.nvertex
+	dm.grow(v + 1)
+	return int64(v)
+}
+
+// RemoveVertex removes all edges of v, which stays as an isolated vertex
+func (dm *DynamicMatching[W]) RemoveVertex(v int64) error {
+	if v < 0 {
+		return fmt.Errorf("%w: %d", ErrNegativeVertex, v)
+	}
+	if v >= int64(dm.nvertex) || len(dm.engine.adjacent[v]) == 0 {
+		return nil
+	}
+	return dm.update(func(e *engine[W]) {
+		for len(e.adjacent[v]) > 0 {
+			dm.remove(e.adjacent[v][len(e.adjacent[v])-1] / 2)
+		}
+	}, false)
+}
+
+// AddEdge adds an edge between u and v, which must not be connected yet
+func (dm *DynamicMatching[W]) AddEdge(u, v int64, w W) error {
+	edge := WeightedEdge[W]{Node1: u, Node2: v, Weight: w}
+	if err := validateEdges(dm.Arithmetic, []WeightedEdge[W]{edge}); err != nil {
+		return err
+	}
+	key := pairKey(u, v)
+	if _, ok := dm.index[key]; ok {
+		return fmt.Errorf("%w: %d -- %d", ErrDuplicateEdge, u, v)
+	}
+	dm.grow(int(max(u, v)) + 1)
+
+	// In maximum cardinality mode a new edge may complete an augmenting path even if it is feasible
+	return dm.update(func(e *engine[W]) {
+		dm.index[key] = len(e.edges)
+		e.insertEdge(edge)
+	}, true)
+}
+
+// RemoveEdge removes the edge between u and v
+func (dm *DynamicMatching[W]) RemoveEdge(u, v int64) error {
+	k, err := dm.find(u, v)
+	if err != nil {
+		return err
+	}
+	return dm.update(func(e *engine[W]) {
+		dm.remove(k)
+	}, false)
+}
+
+// UpdateWeight changes the weight of the edge between u and v
+func (dm *DynamicMatching[W]) UpdateWeight(u, v int64, w W) error {
+	k, err := dm.find(u, v)
+	if err != nil {
+		return err
+	}
+	if !dm.Arithmetic.Valid(w) {
+		return &EdgeError{Index: k, Node1: u, Node2: v, Err: ErrInvalidWeight}
+	}
+	return dm.update(func(e *engine[W]) {
+		e.updateEdge(k, w)
+	}, false)
+}
+
+// remove deletes edge k from the engine and the index, which moves the last edge to position k
+func (dm *DynamicMatching[W]) remove(k int) {
+	e := &dm.engine
+	delete(dm.index, pairKey(e.edges[k].Node1, e.edges[k].Node2))
+	if last := len(e.edges) - 1; k != last {
+		dm.index[pairKey(e.edges[last].Node1, e.edges[last].Node2)] = k
+	}
+	e.deleteEdge(k)
+}
+
+// NumVertices returns the number of vertices, including isolated ones
+func (dm *DynamicMatching[W]) NumVertices() int {
+	return dm.nvertex
+}
+
+// Edges returns a copy of the current edges
+func (dm *DynamicMatching[W]) Edges() []WeightedEdge[W] {
+	return append([]WeightedEdge[W](nil), dm.engine.edges...)
+}
+
+// Mate returns the mate of v, or -1 if v is unmatched or unknown
+func (dm *DynamicMatching[W]) Mate(v int64) int64 {
+	if v < 0 || v >= int64(dm.nvertex) {
+		return -1
+	}
+	return dm.engine.mate[v]
+}
+
+// Pairs returns the matched pairs, smaller vertex first
+func (dm *DynamicMatching[W]) Pairs() []Pair {
+	pairs := make([]Pair, 0)
+	for v, w := range dm.engine.mate[:dm.nvertex] {
+		if w > int64(v) {
+			pairs = append(pairs, Pair{First: int64(v), Second: w})
+		}
+	}
+	return pairs
+}
+
+// TotalWeight returns the weight of the current matching
+func (dm *DynamicMatching[W]) TotalWeight() W {
+	ar := dm.Arithmetic
+	e := &dm.engine
+	total := ar.Zero()
+	for v, w := range e.mate[:dm.nvertex] {
+		if w > int64(v) {
+			total = ar.Add(total, e.edges[e.mateedge[v]].Weight)
+		}
+	}
+	return total
+}
+
+// startDynamic moves the neighbor lists of the engine into adjacent, which edits change in place,
+// and adds isolated vertices up to nvertex. Between runs mate holds vertices, not endpoints
+func (e *engine[W]) startDynamic(nvertex int) {
+	e.adjacent = make([][]int, e.nvertex)
+	for v := range e.adjacent {
+		e.adjacent[v] = append([]int(nil), e.neighbend[e.neighbstart[v]:e.neighbstart[v+1]]...)
+	}
+	if nvertex > e.nvertex {
+		e.growVertices(nvertex)
+	}
+}
+
+// freeDual returns the dual of the free vertices after a run: zero, or in maximum cardinality
+// mode the smallest vertex dual
+func (e *engine[W]) freeDual() W {
+	ar := e.ar
+	d := ar.Zero()
+	if e.opts.maxCardinality {
+		for v := 0; v < e.nvertex; v++ {
+			if v == 0 || ar.Cmp(e.dualvar[v], d) < 0 {
+				d = e.dualvar[v]
+			}
+		}
+	}
+	return d
+}
+
+// growVertices adds isolated free vertices up to n between runs. The blossoms are numbered after
+// the vertices, so they move up by the number of new vertices
+func (e *engine[W]) growVertices(n int) {
+	ar := e.ar
+	old := e.nvertex
+	shift := n - old
+	renumber := func(b int) int {
+		if b >= old {
+			return b + shift
+		}
+		return b
+	}
+	dual := e.freeDual()
+
+	blossomparent := make([]int, 2*n)
+	blossomchilds := make([][]int, 2*n)
+	blossombase := make([]int, 2*n)
+	blossomendps := make([][]int, 2*n)
+	dualvar := make([]W, 2*n)
+	for b := 0; b < 2*n; b++ {
+		blossomparent[b] = -1
+		blossombase[b] = -1
+		dualvar[b] = ar.Zero()
+		if b >= old && b < n {
+			blossombase[b] = b
+			dualvar[b] = dual
+		}
+	}
+	for b := 0; b < 2*old; b++ {
+		t := renumber(b)
+		if p := e.blossomparent[b]; p != -1 {
+			blossomparent[t] = renumber(p)
+		}
+		for i, c := range e.blossomchilds[b] {
+			e.blossomchilds[b][i] = renumber(c)
+		}
+		blossomchilds[t] = e.blossomchilds[b]
+		blossombase[t] = e.blossombase[b]
+		blossomendps[t] = e.blossomendps[b]
+		dualvar[t] = e.dualvar[b]
+	}
+	e.blossomparent, e.blossomchilds, e.blossombase, e.blossomendps, e.dualvar = blossomparent, blossomchilds, blossombase, blossomendps, dualvar
+
+	for v := 0; v < old; v++ {
+		e.inblossom[v] = renumber(e.inblossom[v])
+	}
+	for v := old; v < n; v++ {
+		e.inblossom = append(e.inblossom, v)
+		e.mate = append(e.mate, -1)
+		e.mateedge = append(e.mateedge, -1)
+		e.adjacent = append(e.adjacent, nil)
+	}
+	e.unusedblossoms = e.unusedblossoms[:0]
+	for b := n; b < 2*n; b++ {
+		if blossombase[b] == -1 {
+			e.unusedblossoms = append(e.unusedblossoms, b)
+		}
+	}
+
+	// The labels are reset by the next run
+	e.label = make([]int, 2*n)
+	e.labelend = make([]int, 2*n)
+	e.bestedge = make([]int, 2*n)
+	e.blossombestedges = make([][]int, 2*n)
+	e.bestedgesknown = make([]bool, 2*n)
+	e.bestedgeto = make([]int, 2*n)
+	for b := 0; b < 2*n; b++ {
+		e.labelend[b] = -1
+		e.bestedge[b] = -1
+	}
+	e.nvertex = n
+	e.bindSlacks()
+	if e.pq != nil {
+		e.pq.initQueues(ar, n)
+	}
+}
+
+// beginEdit prepares the engine for edits after a run. The floor of the next run is the dual
+// of the free vertices
+func (e *engine[W]) beginEdit(mwm *MaximumWeightedMatching) {
+	e.mwm = mwm
+	e.beginRun()
+	e.floor = e.freeDual()
+	e.freed = e.freed[:0]
+}
+
+// insertEdge appends edge to the graph and restores the feasibility of its slack
+func (e *engine[W]) insertEdge(edge WeightedEdge[W]) {
+	k := len(e.edges)
+	e.edges = append(e.edges, edge)
+	e.endpoint = append(e.endpoint, edge.Node1, edge.Node2)
+	e.allowedge = append(e.allowedge, false)
+	e.nedges = len(e.edges)
+	e.adjacent[edge.Node1] = append(e.adjacent[edge.Node1], 2*k+1)
+	e.adjacent[edge.Node2] = append(e.adjacent[edge.Node2], 2*k)
+	e.bindSlacks()
+	e.restoreFeasibility(k)
+}
+
+// updateEdge changes the weight of edge k. A matched edge or an edge on the cycle of a blossom
+// loses its tightness, so the blossoms containing both its ends are dissolved and a matched edge
+// is unmatched first
+func (e *engine[W]) updateEdge(k int, w W) {
+	e.detachEdge(k)
+	e.edges[k].Weight = w
+	e.restoreFeasibility(k)
+}
+
+// deleteEdge removes edge k after detaching it like updateEdge. The last edge takes its position
+func (e *engine[W]) deleteEdge(k int) {
+	e.detachEdge(k)
+	u, v := int(e.edges[k].Node1), int(e.edges[k].Node2)
+	e.adjacent[u] = removeEndpoint(e.adjacent[u], 2*k+1)
+	e.adjacent[v] = removeEndpoint(e.adjacent[v], 2*k)
+
+	last := len(e.edges) - 1
+	if k != last {
+		moved := e.edges[last]
+		a, b := int(moved.Node1), int(moved.Node2)
+		e.edges[k] = moved
+		e.endpoint[2*k], e.endpoint[2*k+1] = moved.Node1, moved.Node2
+		e.allowedge[k] = e.allowedge[last]
+		replaceEndpoint(e.adjacent[a], 2*last+1, 2*k+1)
+		replaceEndpoint(e.adjacent[b], 2*last, 2*k)
+		if e.mateedge[a] == last {
+			e.mateedge[a], e.mateedge[b] = k, k
+		}
+		if c := e.commonBlossom(a, b); c != -1 {
+			for i, p := range e.blossomendps[c] {
+				if p/2 == last {
+					e.blossomendps[c][i] = 2*k + p%2
+				}
+			}
+		}
+	}
+	e.edges = e.edges[:last]
+	e.endpoint = e.endpoint[:2*last]
+	e.allowedge = e.allowedge[:last]
+	e.nedges = last
+	e.bindSlacks()
+}
+
+// detachEdge dissolves the blossoms containing both ends of edge k and unmatches it, if it is
+// matched or on the cycle of a blossom
+func (e *engine[W]) detachEdge(k int) {
+	u, v := int(e.edges[k].Node1), int(e.edges[k].Node2)
+	if e.mateedge[u] != k && e.cycleBlossom(k) == -1 {
+		return
+	}
+	for e.inblossom[u] == e.inblossom[v] {
+		e.dissolveBlossom(e.inblossom[u])
+	}
+	if e.mateedge[u] == k {
+		e.freeVertex(u)
+	}
+}
+
+// restoreFeasibility raises the dual of an end of the unmatched edge k if the edge has negative
+// slack. That end is unmatched first, and the blossoms containing it are dissolved, so that no
+// tight edge loses its tightness
+func (e *engine[W]) restoreFeasibility(k int) {
+	ar := e.ar
+	if ar.Cmp(e.dynamicSlack(k), ar.Zero()) >= 0 {
+		return
+	}
+	edge := e.edges[k]
+	u, v := int(edge.Node1), int(edge.Node2)
+	if e.inblossom[u] != u && e.inblossom[v] == v {
+		u, v = v, u
+	}
+	for e.inblossom[u] != u {
+		e.dissolveBlossom(e.inblossom[u])
+	}
+	e.freeVertex(u)
+	if need := ar.Sub(ar.Double(edge.Weight), e.dualvar[v]); ar.Cmp(need, e.dualvar[u]) > 0 {
+		e.dualvar[u] = need
+	}
+}
+
+// dissolveBlossom expands the top-level blossom b between runs. Its dual moves to the vertices it
+// contains, which keeps the slack of the edges inside it, and a positive dual frees its base
+func (e *engine[W]) dissolveBlossom(b int) {
+	ar := e.ar
+	if z := e.dualvar[b]; ar.Cmp(z, ar.Zero()) != 0 {
+		e.leaves = e.appendLeaves(e.leaves[:0], b)
+		for _, v := range e.leaves {
+			e.dualvar[v] = ar.Add(e.dualvar[v], z)
+		}
+		e.dualvar[b] = ar.Zero()
+		e.freeVertex(e.blossombase[b])
+	}
+	e.expandBlossom(b, true)
+}
+
+// freeVertex unmatches v and records it and its former mate in freed
+func (e *engine[W]) freeVertex(v int) {
+	if w := e.mate[v]; w != -1 {
+		e.mate[w], e.mateedge[w] = -1, -1
+		e.mate[v], e.mateedge[v] = -1, -1
+		e.freed = append(e.freed, int(w))
+	}
+	e.freed = append(e.freed, v)
+}
+
+// settleFreed prepares the freed vertices as roots of the next run and reports whether there is a
+// root, a free vertex above the floor. Like in seed, a root must lie an even distance above the
+// floor; raising its dual to get there dissolves the blossoms containing it
+func (e *engine[W]) settleFreed() bool {
+	ar := e.ar
+	roots := false
+	for i := 0; i < len(e.freed); i++ {
+		v := e.freed[i]
+		if e.mate[v] != -1 || ar.Cmp(e.dualvar[v], e.floor) <= 0 {
+			continue
+		}
+		if d := e.evenAboveFloor(e.dualvar[v]); ar.Cmp(d, e.dualvar[v]) != 0 {
+			for e.inblossom[v] != v {
+				e.dissolveBlossom(e.inblossom[v])
+			}
+			e.dualvar[v] = e.evenAboveFloor(e.dualvar[v])
+		}
+		roots = true
+	}
+	e.freed = e.freed[:0]
+	return roots
+}
+
+// resume re-optimizes after edits from the matching, the blossoms and the duals of the previous
+// run. Like after seed, the free vertices above the floor are the roots of the first stages
+func (e *engine[W]) resume() {
+	nvertex := e.nvertex
+	for v := 0; v < nvertex; v++ {
+		if k := e.mateedge[v]; k != -1 {
+			e.mate[v] = int64(2 * k)
+			if e.edges[k].Node1 == int64(v) {
+				e.mate[v]++
+			}
+		}
+	}
+	for b := 0; b < 2*nvertex; b++ {
+		e.label[b] = 0
+		e.labelend[b] = -1
+	}
+	e.queue = e.queue[:0]
+	e.pq = nil
+	if e.mwm.Engine == PriorityQueueEngine {
+		e.pq = &e.queues
+		e.pq.initQueues(e.ar, nvertex)
+	}
+	e.warm = true
+	e.maxCardinality = false
+	e.run()
+}
+
+// commonBlossom returns the smallest blossom containing both u and v, or -1
+func (e *engine[W]) commonBlossom(u, v int) int {
+	du, dv := 0, 0
+	for b := e.blossomparent[u]; b != -1; b = e.blossomparent[b] {
+		du++
+	}
+	for b := e.blossomparent[v]; b != -1; b = e.blossomparent[b] {
+		dv++
+	}
+	for ; du > dv; du-- {
+		u = e.blossomparent[u]
+	}
+	for ; dv > du; dv-- {
+		v = e.blossomparent[v]
+	}
+	for u != v {
+		u, v = e.blossomparent[u], e.blossomparent[v]
+	}
+	return u
+}
+
+// cycleBlossom returns the blossom whose cycle contains edge k, or -1
+func (e *engine[W]) cycleBlossom(k int) int {
+	b := e.commonBlossom(int(e.edges[k].Node1), int(e.edges[k].Node2))
+	if b != -1 {
+		for _, p := range e.blossomendps[b] {
+			if p/2 == k {
+				return b
+			}
+		}
+	}
+	return -1
+}
+
+// dynamicSlack returns the slack of edge k including the duals of the blossoms containing both its ends
+func (e *engine[W]) dynamicSlack(k int) W {
+	ar := e.ar
+	slack := e.slack(k)
+	for b := e.commonBlossom(int(e.edges[k].Node1), int(e.edges[k].Node2)); b != -1; b = e.blossomparent[b] {
+		slack = ar.Add(slack, ar.Double(e.dualvar[b]))
+	}
+	return slack
+}
+
+// removeEndpoint deletes p from the endpoint list s without keeping the order
+func removeEndpoint(s []int, p int) []int {
+	for i, q := range s {
+		if q == p {
+			s[i] = s[len(s)-1]
+			return s[:len(s)-1]
+		}
+	}
+	return s
+}
+
+// replaceEndpoint replaces p by q in the endpoint list s
+func replaceEndpoint(s []int, p, q int) {
+	for i, r := range s {
+		if r == p {
+			s[i] = q
+			return
+		}
+	}
+}
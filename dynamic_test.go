@@ -0,0 +1,171 @@
+package mwm
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// TestDynamicMatchingRandom - test random edit sequences on both engines against exhaustive search
+func TestDynamicMatchingRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(20))
+	for _, engine := range []Engine{ScanEngine, PriorityQueueEngine} {
+		matcher := NewMaximumWeightedMatching()
+		matcher.Engine = engine
+		for i := 0; i < 40; i++ {
+			nvertex := 2 + rng.Intn(10)
+			maxCardinality := i%2 == 1
+			dm, err := NewDynamicMatching(matcher, Int64Arithmetic{}, randomEdges(rng, nvertex, 0.4, -10, 50), maxCardinality)
+			if err != nil {
+				t.Fatalf("Engine %d, iteration %d: unexpected error: %v", engine, i, err)
+			}
+			for dm.NumVertices() < nvertex {
+				dm.AddVertex()
+			}
+
+			for step := 0; step < 100; step++ {
+				u, v := rng.Int63n(int64(dm.NumVertices())), rng.Int63n(int64(dm.NumVertices()))
+				weight := -10 + rng.Int63n(61)
+				_, connected := dm.index[pairKey(u, v)]
+				switch {
+				case u == v && dm.NumVertices() < 14 && rng.Intn(2) == 0:
+					dm.AddVertex()
+				case u == v:
+					err = dm.RemoveVertex(u)
+				case !connected:
+					err = dm.AddEdge(u, v, weight)
+				case rng.Intn(2) == 0:
+					err = dm.RemoveEdge(u, v)
+				default:
+					err = dm.UpdateWeight(u, v, weight)
+				}
+				where := fmt.Sprintf("Engine %d, iteration %d, step %d", engine, i, step)
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %v", where, err)
+				}
+
+				pairs := dm.Pairs()
+				checkBruteForce(t, where, dm.NumVertices(), dm.Edges(), maxCardinality, len(pairs), dm.TotalWeight())
+				for _, pair := range pairs {
+					if dm.Mate(pair.First) != pair.Second || dm.Mate(pair.Second) != pair.First {
+						t.Fatalf("%s: inconsistent pair %v", where, pair)
+					}
+					if _, ok := dm.index[pair]; !ok {
+						t.Fatalf("%s: pair %v is no edge", where, pair)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestDynamicMatchingLocalRepair - test that lowering an edge outside the matching and the blossoms
+// needs no run, and that other edits re-optimize from the kept matching
+func TestDynamicMatchingLocalRepair(t *testing.T) {
+	rng := rand.New(rand.NewSource(22))
+	matcher := NewMaximumWeightedMatching()
+	matcher.Engine = PriorityQueueEngine
+	dm, err := NewDynamicMatching(matcher, Int64Arithmetic{}, randomEdges(rng, 300, 0.02, 1, 1000), false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for step := 0; step < 200; step++ {
+		k := rng.Intn(len(dm.engine.edges))
+		edge := dm.engine.edges[k]
+		loose := dm.Mate(edge.Node1) != edge.Node2 && dm.engine.cycleBlossom(k) == -1
+		pairs := len(dm.Pairs())
+		if err := dm.UpdateWeight(edge.Node1, edge.Node2, max(1, edge.Weight-1-rng.Int63n(100))); err != nil {
+			t.Fatalf("Step %d: unexpected error: %v", step, err)
+		}
+		if loose && (dm.engine.stage != -1 || len(dm.Pairs()) != pairs) {
+			t.Fatalf("Step %d: lowering an edge outside the matching and the blossoms ran %d stages", step, dm.engine.stage+1)
+		}
+		expected, err := matcher.MaxWeightMatchingE(dm.Edges(), false)
+		if err != nil {
+			t.Fatalf("Step %d: unexpected error: %v", step, err)
+		}
+		checkOptimum(t, fmt.Sprintf("Step %d", step), nil, false, expected.Cardinality, expected.TotalWeight, len(dm.Pairs()), dm.TotalWeight())
+	}
+}
+
+// TestDynamicMatchingVertices - test adding and removing vertices
+func TestDynamicMatchingVertices(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	dm, err := NewDynamicMatching(matcher, Int64Arithmetic{}, []GraphEdge{{Node1: 0, Node2: 1, Weight: 5}}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	v := dm.AddVertex()
+	if v != 2 || dm.NumVertices() != 3 || dm.Mate(v) != -1 {
+		t.Fatalf("Expected free vertex 2, got %d of %d vertices with mate %d", v, dm.NumVertices(), dm.Mate(v))
+	}
+	if err := dm.AddEdge(1, v, 8); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if dm.Mate(1) != v || dm.TotalWeight() != 8 {
+		t.Errorf("Expected edge 1 -- 2 of weight 8, got mate %d and weight %d", dm.Mate(1), dm.TotalWeight())
+	}
+	if err := dm.RemoveVertex(v); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if dm.Mate(0) != 1 || dm.TotalWeight() != 5 || len(dm.Edges()) != 1 {
+		t.Errorf("Expected edge 0 -- 1 of weight 5, got mate %d, weight %d and %d edges", dm.Mate(0), dm.TotalWeight(), len(dm.Edges()))
+	}
+}
+
+// TestDynamicMatchingErrors - test that invalid edits are rejected
+func TestDynamicMatchingErrors(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{{Node1: 0, Node2: 1, Weight: 5}, {Node1: 1, Node2: 0, Weight: 3}}
+	if _, err := NewDynamicMatching(matcher, Int64Arithmetic{}, edges, false); !errors.Is(err, ErrDuplicateEdge) {
+		t.Errorf("Expected ErrDuplicateEdge, got %v", err)
+	}
+
+	dm, err := NewDynamicMatching(matcher, Int64Arithmetic{}, edges[:1], false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := dm.AddEdge(1, 0, 2); !errors.Is(err, ErrDuplicateEdge) {
+		t.Errorf("Expected ErrDuplicateEdge, got %v", err)
+	}
+	if err := dm.AddEdge(1, 1, 2); !errors.Is(err, ErrSelfLoop) {
+		t.Errorf("Expected ErrSelfLoop, got %v", err)
+	}
+	if err := dm.RemoveEdge(0, 2); !errors.Is(err, ErrUnknownEdge) {
+		t.Errorf("Expected ErrUnknownEdge, got %v", err)
+	}
+	if err := dm.UpdateWeight(2, 3, 1); !errors.Is(err, ErrUnknownEdge) {
+		t.Errorf("Expected ErrUnknownEdge, got %v", err)
+	}
+	if err := dm.RemoveVertex(-1); !errors.Is(err, ErrNegativeVertex) {
+		t.Errorf("Expected ErrNegativeVertex, got %v", err)
+	}
+}
+
+// BenchmarkDynamicMatching - benchmark single weight updates on a large sparse graph with the priority queue engine
+func BenchmarkDynamicMatching(b *testing.B) {
+	rng := rand.New(rand.NewSource(21))
+	edges := make([]GraphEdge, 0)
+	connected := make(map[Pair]bool)
+	for v := int64(1); v < 20000; v++ {
+		for c := 0; c < 3; c++ {
+			if u := rng.Int63n(v); !connected[Pair{First: u, Second: v}] {
+				connected[Pair{First: u, Second: v}] = true
+				edges = append(edges, GraphEdge{Node1: u, Node2: v, Weight: 1 + rng.Int63n(1000)})
+			}
+		}
+	}
+	matcher := NewMaximumWeightedMatching()
+	matcher.Engine = PriorityQueueEngine
+	dm, err := NewDynamicMatching(matcher, Int64Arithmetic{}, edges, false)
+	if err != nil {
+		b.Fatalf("Unexpected error: %v", err)
+	}
+	for b.Loop() {
+		edge := dm.engine.edges[rng.Intn(len(dm.engine.edges))]
+		if err := dm.UpdateWeight(edge.Node1, edge.Node2, 1+rng.Int63n(1000)); err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package mwm
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNegativeVertex is reported for an edge with a negative vertex number
+	ErrNegativeVertex = errors.New("mwm: negative vertex")
+	// ErrVertexRange is reported for an edge with a vertex number above MaxVertex
+	ErrVertexRange = errors.New("mwm: vertex number out of range")
+	// ErrSelfLoop is reported for an edge connecting a vertex to itself
+	ErrSelfLoop = errors.New("mwm: self-loop")
+	// ErrInvalidWeight is reported for a weight rejected by Arithmetic.Valid, such as NaN
+	ErrInvalidWeight = errors.New("mwm: invalid weight")
+	// ErrWeightOverflow is reported when a weight, dual variable or total weight does not fit the weight type
+	ErrWeightOverflow = errors.New("mwm: weight overflow")
+	// ErrNoPerfectMatching is reported when the graph has no matching covering all of its vertices
+	ErrNoPerfectMatching = errors.New("mwm: no perfect matching")
+	// ErrCardinalityUnreachable is reported when no matching has the requested number of edges
+	ErrCardinalityUnreachable = errors.New("mwm: requested cardinality is unreachable")
+	// ErrInvalidWarmStart is reported for a warm start whose mate array is not symmetric
+	ErrInvalidWarmStart = errors.New("mwm: invalid warm start")
+	// ErrDuplicateEdge is reported when an edge is added between two vertices that are already connected
+	ErrDuplicateEdge = errors.New("mwm: duplicate edge")
+	// ErrUnknownEdge is reported when an edge to be changed or removed does not exist
+	ErrUnknownEdge = errors.New("mwm: unknown edge")
+	// ErrInfeasibleConstraints is reported for forced and forbidden edges that no matching can satisfy
+	ErrInfeasibleConstraints = errors.New("mwm: infeasible edge constraints")
+	// ErrUncoveredVertex is reported when no matching covers all required vertices
+	ErrUncoveredVertex = errors.New("mwm: required vertex cannot be covered")
+	// ErrNotBipartite is reported for an edge within one side of a bipartition, or closing an odd cycle
+	ErrNotBipartite = errors.New("mwm: graph is not bipartite")
+	// ErrInvalidMatrix is reported for a ragged assignment matrix or a forbidden mask of another shape
+	ErrInvalidMatrix = errors.New("mwm: invalid matrix")
+	// ErrInternalInvariant is reported when the algorithm detects an inconsistent internal state
+	ErrInternalInvariant = errors.New("mwm: internal invariant violated")
+)
+
+// EdgeError describes an invalid input edge
+type EdgeError struct {
+	Index int
+	Node1 int64
+	Node2 int64
+	Err   error
+}
+
+func (e *EdgeError) Error() string {
+	return fmt.Sprintf("%v: edge %d (%d -- %d)", e.Err, e.Index, e.Node1, e.Node2)
+}
+
+func (e *EdgeError) Unwrap() error {
+	return e.Err
+}
+
+// CoverageError lists the required vertices left uncovered by the best matching found
+type CoverageError struct {
+	Uncovered []int64
+}
+
+func (e *CoverageError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrUncoveredVertex, e.Uncovered)
+}
+
+func (e *CoverageError) Unwrap() error {
+	return ErrUncoveredVertex
+}
+
+// InvariantError describes a violated internal invariant and where it was detected
+type InvariantError struct {
+	// Stage is the main loop stage, or -1 if the failure happened before the first stage
+	Stage int
+	// Step names the part of the algorithm that detected the failure
+	Step string
+}
+
+func (e *InvariantError) Error() string {
+	return fmt.Sprintf("%v: stage %d: %s", ErrInternalInvariant, e.Stage, e.Step)
+}
+
+func (e *InvariantError) Unwrap() error {
+	return ErrInternalInvariant
+}
+
+// MaxVertex is the largest vertex number of an edge. The solvers index arrays by vertex number,
+// and blossoms are numbered after the vertices, so twice the number of vertices must fit an int
+const MaxVertex = 1<<30 - 1
+
+// validateEdges checks that every edge connects two distinct vertices in [0, MaxVertex] and has a valid weight
+func validateEdges[W any](ar Arithmetic[W], edges []WeightedEdge[W]) error {
+	for k, edge := range edges {
+		if edge.Node1 < 0 || edge.Node2 < 0 {
+			return &EdgeError{Index: k, Node1: edge.Node1, Node2: edge.Node2, Err: ErrNegativeVertex}
+		}
+		if edge.Node1 > MaxVertex || edge.Node2 > MaxVertex {
+			return &EdgeError{Index: k, Node1: edge.Node1, Node2: edge.Node2, Err: ErrVertexRange}
+		}
+		if edge.Node1 == edge.Node2 {
+			return &EdgeError{Index: k, Node1: edge.Node1, Node2: edge.Node2, Err: ErrSelfLoop}
+		}
+		if !ar.Valid(edge.Weight) {
+			return &EdgeError{Index: k, Node1: edge.Node1, Node2: edge.Node2, Err: ErrInvalidWeight}
+		}
+	}
+	return nil
+}
+
+// recoverFailure converts a panic raised by a violated invariant or an arithmetic overflow into an error
+func recoverFailure(err *error) {
+	if r := recover(); r != nil {
+		if r == ErrWeightOverflow {
+			*err = ErrWeightOverflow
+			return
+		}
+		invariantErr, ok := r.(*InvariantError)
+		if !ok {
+			panic(r)
+		}
+		*err = invariantErr
+	}
+}
@@ -0,0 +1,94 @@
+package mwm
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// TestNegativeVertexError - test that a negative vertex is reported instead of crashing
+func TestNegativeVertexError(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{
+		{Node1: 0, Node2: 1, Weight: 5},
+		{Node1: -1, Node2: 1, Weight: 7},
+	}
+	result, err := matcher.MaxWeightMatchingE(edges, false)
+	if result != nil {
+		t.Errorf("Expected no result, got %v", result)
+	}
+	if !errors.Is(err, ErrNegativeVertex) {
+		t.Fatalf("Expected ErrNegativeVertex, got %v", err)
+	}
+	var edgeErr *EdgeError
+	if !errors.As(err, &edgeErr) || edgeErr.Index != 1 {
+		t.Errorf("Expected EdgeError for edge 1, got %v", err)
+	}
+}
+
+// TestVertexRangeError - test that a vertex number above MaxVertex is reported instead of crashing
+func TestVertexRangeError(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{{Node1: 0, Node2: math.MaxInt64, Weight: 1}}
+	_, err := matcher.MaxWeightMatchingE(edges, false)
+	var edgeErr *EdgeError
+	if !errors.Is(err, ErrVertexRange) || !errors.As(err, &edgeErr) || edgeErr.Index != 0 {
+		t.Errorf("Expected an EdgeError with ErrVertexRange, got %v", err)
+	}
+	if _, err := matcher.MaxWeightBipartiteMatching(edges, nil, false); !errors.Is(err, ErrVertexRange) {
+		t.Errorf("Expected ErrVertexRange from the bipartite solver, got %v", err)
+	}
+	if _, err := NewDynamicMatching(matcher, Int64Arithmetic{}, []GraphEdge{{Node1: MaxVertex + 1, Node2: 0, Weight: 1}}, false); !errors.Is(err, ErrVertexRange) {
+		t.Errorf("Expected ErrVertexRange from NewDynamicMatching, got %v", err)
+	}
+}
+
+// TestSelfLoopError - test that a self-loop is rejected
+func TestSelfLoopError(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{
+		{Node1: 2, Node2: 2, Weight: 5},
+	}
+	_, err := matcher.MaxWeightMatchingE(edges, false)
+	if !errors.Is(err, ErrSelfLoop) {
+		t.Errorf("Expected ErrSelfLoop, got %v", err)
+	}
+}
+
+// TestMaxWeightMatchingE - test that valid input gives the same result as MaxWeightMatching
+func TestMaxWeightMatchingE(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{
+		{Node1: 1, Node2: 2, Weight: 9},
+		{Node1: 1, Node2: 3, Weight: 8},
+		{Node1: 2, Node2: 3, Weight: 10},
+		{Node1: 1, Node2: 4, Weight: 5},
+		{Node1: 4, Node2: 5, Weight: 4},
+		{Node1: 1, Node2: 6, Weight: 3},
+	}
+	result, err := matcher.MaxWeightMatchingE(edges, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := matcher.MaxWeightMatching(edges, false)
+	if !reflect.DeepEqual(result.Pairs, expected) {
+		t.Errorf("Expected %v, got %v", expected, result.Pairs)
+	}
+}
+
+// TestRecoverFailure - test that invariant panics become InvariantError values
+func TestRecoverFailure(t *testing.T) {
+	run := func() (err error) {
+		defer recoverFailure(&err)
+		panic(&InvariantError{Stage: 3, Step: "delta3: odd slack"})
+	}
+	err := run()
+	if !errors.Is(err, ErrInternalInvariant) {
+		t.Fatalf("Expected ErrInternalInvariant, got %v", err)
+	}
+	var invariantErr *InvariantError
+	if !errors.As(err, &invariantErr) || invariantErr.Stage != 3 || invariantErr.Step != "delta3: odd slack" {
+		t.Errorf("Expected stage 3 and step, got %v", err)
+	}
+}
@@ -0,0 +1,23 @@
+package mwm
+
+// FloatGraphEdge represents a graph edge with two nodes and a floating-point weight
+type FloatGraphEdge = WeightedEdge[float64]
+
+// FloatMatchingResult describes a matching with floating-point weights
+type FloatMatchingResult = WeightedMatchingResult[float64]
+
+// MaxWeightMatchingFloat returns the maximum weighted matching for floating-point weights.
+//
+// Slacks, deltas and dual variables that differ by at most Epsilon (DefaultEpsilon when zero)
+// are treated as equal. The returned matching is then optimal for weights that differ from
+// the input by at most Epsilon per edge, so its total weight is within n*Epsilon of the
+// optimum, where n is the number of vertices, provided the rounding errors accumulated
+// in the dual variables stay below Epsilon. Epsilon should therefore be well above the
+// precision of the weights and well below the smallest weight difference that matters
+func (mwm *MaximumWeightedMatching) MaxWeightMatchingFloat(edges []FloatGraphEdge, maxCardinality bool) (*FloatMatchingResult, error) {
+	epsilon := mwm.Epsilon
+	if epsilon == 0 {
+		epsilon = DefaultEpsilon
+	}
+	return MaxWeightMatchingWith(mwm, Float64Arithmetic{Epsilon: epsilon}, edges, maxCardinality)
+}
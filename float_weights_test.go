@@ -0,0 +1,65 @@
+package mwm
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestFloatWeights - test matching with similarity scores in [0,1]
+func TestFloatWeights(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []FloatGraphEdge{
+		{Node1: 0, Node2: 1, Weight: 0.10},
+		{Node1: 1, Node2: 2, Weight: 0.11},
+		{Node1: 2, Node2: 3, Weight: 0.12},
+		{Node1: 0, Node2: 3, Weight: 0.13},
+	}
+	result, err := matcher.MaxWeightMatchingFloat(edges, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []Pair{{First: 0, Second: 3}, {First: 1, Second: 2}}
+	if len(result.Pairs) != 2 || result.Pairs[0] != expected[0] || result.Pairs[1] != expected[1] {
+		t.Errorf("Expected %v, got %v", expected, result.Pairs)
+	}
+	if math.Abs(result.TotalWeight-0.24) > 1e-12 {
+		t.Errorf("Expected total weight 0.24, got %v", result.TotalWeight)
+	}
+}
+
+// TestFloatWeightsRandom - test random similarity scores against exhaustive search
+func TestFloatWeightsRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	matcher := NewMaximumWeightedMatching()
+	for i := 0; i < 300; i++ {
+		nvertex := 2 + rng.Intn(12)
+		edges := make([]FloatGraphEdge, 0)
+		for _, edge := range randomEdges(rng, nvertex, 0.3+0.6*rng.Float64(), 0, 1) {
+			edges = append(edges, FloatGraphEdge{Node1: edge.Node1, Node2: edge.Node2, Weight: rng.Float64()})
+		}
+		maxCardinality := i%2 == 1
+		result, err := matcher.MaxWeightMatchingFloat(edges, maxCardinality)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		cardinality, weight := bruteForceMatching(nvertex, edges, maxCardinality)
+		if math.Abs(result.TotalWeight-weight) > float64(nvertex)*DefaultEpsilon ||
+			(maxCardinality && result.Cardinality != cardinality) {
+			t.Fatalf("Iteration %d (maxCardinality=%t): expected %d edges of weight %v, got %d edges of weight %v",
+				i, maxCardinality, cardinality, weight, result.Cardinality, result.TotalWeight)
+		}
+	}
+}
+
+// TestFloatWeightsInvalid - test that NaN weights are rejected
+func TestFloatWeightsInvalid(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []FloatGraphEdge{
+		{Node1: 0, Node2: 1, Weight: math.NaN()},
+	}
+	if _, err := matcher.MaxWeightMatchingFloat(edges, false); !errors.Is(err, ErrInvalidWeight) {
+		t.Errorf("Expected ErrInvalidWeight, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package mwm
+
+// KeyedEdge represents a graph edge between two vertices identified by arbitrary keys
+type KeyedEdge[K comparable] struct {
+	Node1  K
+	Node2  K
+	Weight int64
+}
+
+// KeyedPair represents a pair of connected vertices identified by keys
+type KeyedPair[K comparable] struct {
+	First  K
+	Second K
+}
+
+// KeyedMatchingResult describes a matching on a graph with arbitrary vertex keys
+type KeyedMatchingResult[K comparable] struct {
+	// Pairs lists the matched vertex pairs
+	Pairs []KeyedPair[K]
+	// EdgeIndices holds, for every pair, the index of the matched edge in the input slice
+	EdgeIndices []int
+	// Weights holds, for every pair, the weight of the matched edge
+	Weights []int64
+	// TotalWeight is the sum of Weights
+	TotalWeight int64
+	// Cardinality is the number of matched edges
+	Cardinality int
+	// Keys maps the dense vertex numbers of Dense back to the original keys
+	Keys []K
+	// Dense is the matching computed on the compacted graph, including its dual solution
+	Dense *MatchingResult
+}
+
+// Matcher finds maximum weighted matchings on graphs whose vertices are identified by
+// arbitrary comparable keys such as strings or UUIDs. Keys are compacted to dense vertex
+// numbers internally, so memory usage depends only on the number of distinct keys
+type Matcher[K comparable] struct {
+	// Matching is the algorithm instance used on the compacted graph
+	Matching *MaximumWeightedMatching
+}
+
+// NewMatcher creates a new matcher for vertices of key type K
+func NewMatcher[K comparable]() *Matcher[K] {
+	return &Matcher[K]{Matching: NewMaximumWeightedMatching()}
+}
+
+// MaxWeightMatching returns the maximum weighted matching as a list of key pairs
+func (m *Matcher[K]) MaxWeightMatching(edges []KeyedEdge[K], maxCardinality bool) ([]KeyedPair[K], error) {
+	result, err := m.MaxWeightMatchingResult(edges, maxCardinality)
+	if err != nil {
+		return nil, err
+	}
+	return result.Pairs, nil
+}
+
+// MaxWeightMatchingResult returns the maximum weighted matching together with
+// the matched edge indices and weights
+func (m *Matcher[K]) MaxWeightMatchingResult(edges []KeyedEdge[K], maxCardinality bool) (*KeyedMatchingResult[K], error) {
+	denseEdges, keys, err := compactEdges(edges)
+	if err != nil {
+		return nil, err
+	}
+
+	dense, err := m.Matching.MaxWeightMatchingE(denseEdges, maxCardinality)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &KeyedMatchingResult[K]{
+		Pairs:       make([]KeyedPair[K], len(dense.Pairs)),
+		EdgeIndices: dense.EdgeIndices,
+		Weights:     dense.Weights,
+		TotalWeight: dense.TotalWeight,
+		Cardinality: dense.Cardinality,
+		Keys:        keys,
+		Dense:       dense,
+	}
+	for i, pair := range dense.Pairs {
+		result.Pairs[i] = KeyedPair[K]{First: keys[pair.First], Second: keys[pair.Second]}
+	}
+
+	return result, nil
+}
+
+// compactEdges numbers the keys in order of first appearance and returns the
+// edges over these dense numbers together with the key of every number.
+// A self-loop is reported as an EdgeError with the dense number of its key
+func compactEdges[K comparable](edges []KeyedEdge[K]) ([]GraphEdge, []K, error) {
+	index := make(map[K]int64)
+	keys := make([]K, 0)
+	denseEdges := make([]GraphEdge, len(edges))
+
+	vertex := func(key K) int64 {
+		v, ok := index[key]
+		if !ok {
+			v = int64(len(keys))
+			index[key] = v
+			keys = append(keys, key)
+		}
+		return v
+	}
+
+	for k, edge := range edges {
+		denseEdges[k] = GraphEdge{Node1: vertex(edge.Node1), Node2: vertex(edge.Node2), Weight: edge.Weight}
+		if edge.Node1 == edge.Node2 {
+			return nil, nil, &EdgeError{Index: k, Node1: denseEdges[k].Node1, Node2: denseEdges[k].Node2, Err: ErrSelfLoop}
+		}
+	}
+
+	return denseEdges, keys, nil
+}
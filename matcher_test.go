@@ -0,0 +1,63 @@
+package mwm
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// TestMatcherStringKeys - test matching on string vertex keys
+func TestMatcherStringKeys(t *testing.T) {
+	matcher := NewMatcher[string]()
+	edges := []KeyedEdge[string]{
+		{Node1: "alice", Node2: "bob", Weight: 10},
+		{Node1: "bob", Node2: "carol", Weight: 11},
+		{Node1: "carol", Node2: "dave", Weight: 12},
+		{Node1: "alice", Node2: "dave", Weight: 13},
+	}
+	pairs, err := matcher.MaxWeightMatching(edges, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fmt.Println("String keys:", pairs)
+	expected := []KeyedPair[string]{{First: "alice", Second: "dave"}, {First: "bob", Second: "carol"}}
+	if !reflect.DeepEqual(pairs, expected) {
+		t.Errorf("Expected %v, got %v", expected, pairs)
+	}
+}
+
+// TestMatcherSparseIDs - test that huge vertex numbers do not allocate huge arrays
+func TestMatcherSparseIDs(t *testing.T) {
+	matcher := NewMatcher[int64]()
+	edges := []KeyedEdge[int64]{
+		{Node1: 1_000_000_000, Node2: 1_000_000_001, Weight: 5},
+	}
+	result, err := matcher.MaxWeightMatchingResult(edges, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []KeyedPair[int64]{{First: 1_000_000_000, Second: 1_000_000_001}}
+	if !reflect.DeepEqual(result.Pairs, expected) {
+		t.Errorf("Expected %v, got %v", expected, result.Pairs)
+	}
+	if len(result.Dense.Mate) != 2 {
+		t.Errorf("Expected 2 dense vertices, got %d", len(result.Dense.Mate))
+	}
+	if result.TotalWeight != 5 || !reflect.DeepEqual(result.EdgeIndices, []int{0}) {
+		t.Errorf("Expected edge 0 with total weight 5, got %v", result)
+	}
+}
+
+// TestMatcherSelfLoop - test that self-loops are rejected
+func TestMatcherSelfLoop(t *testing.T) {
+	matcher := NewMatcher[string]()
+	edges := []KeyedEdge[string]{
+		{Node1: "a", Node2: "a", Weight: 1},
+	}
+	_, err := matcher.MaxWeightMatching(edges, false)
+	var edgeErr *EdgeError
+	if !errors.Is(err, ErrSelfLoop) || !errors.As(err, &edgeErr) || edgeErr.Index != 0 {
+		t.Errorf("Expected an EdgeError with ErrSelfLoop, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package mwm
+
+// WeightedMatchingResult describes a matching with weights of type W together with the data needed to interpret it
+type WeightedMatchingResult[W any] struct {
+	// Pairs lists the matched vertex pairs ordered by their first vertex
+	Pairs []Pair
+	// EdgeIndices holds, for every pair, the index of the matched edge in the input slice
+	EdgeIndices []int
+	// Weights holds, for every pair, the weight of the matched edge
+	Weights []W
+	// TotalWeight is the sum of Weights
+	TotalWeight W
+	// Cardinality is the number of matched edges
+	Cardinality int
+	// Mate maps every vertex to its partner, or to -1 when the vertex is unmatched
+	Mate []int64
+	// MaxCardinality reports whether the matching was computed in maximum cardinality mode
+	MaxCardinality bool
+	// VertexDuals holds the dual variable of every vertex at termination.
+	// Duals are kept at twice their LP value, so for every edge (i, j)
+	// VertexDuals[i] + VertexDuals[j] - 2*weight + 2*(sum of Dual of blossoms containing i and j) >= 0
+	VertexDuals []W
+	// Blossoms holds the laminar family of blossoms remaining at termination
+	Blossoms []WeightedBlossom[W]
+	// Stats holds the solver statistics if MaximumWeightedMatching.CollectStats was set, or nil
+	Stats *SolverStats
+}
+
+// MatchingResult describes a matching together with the data needed to interpret it
+type MatchingResult = WeightedMatchingResult[int64]
+
+// WeightedBlossom describes a blossom of the final dual solution
+type WeightedBlossom[W any] struct {
+	// Dual is the dual variable of the blossom
+	Dual W
+	// Parent is the index of the enclosing blossom in MatchingResult.Blossoms, or -1 for a top-level blossom
+	Parent int
+	// Vertices lists all vertices contained in the blossom
+	Vertices []int64
+}
+
+// Blossom describes a blossom of the final dual solution
+type Blossom = WeightedBlossom[int64]
+
+// newMatchingResult builds a result from the final state of the algorithm
+func newMatchingResult[W any](ar Arithmetic[W], edges []WeightedEdge[W], sol *solution[W], maxCardinality bool) *WeightedMatchingResult[W] {
+	result := &WeightedMatchingResult[W]{
+		Pairs:          make([]Pair, 0),
+		EdgeIndices:    make([]int, 0),
+		Weights:        make([]W, 0),
+		TotalWeight:    ar.Zero(),
+		Mate:           sol.mate,
+		MaxCardinality: maxCardinality,
+		VertexDuals:    sol.vertexDuals,
+		Blossoms:       sol.blossoms,
+		Stats:          sol.stats,
+	}
+
+	for v, w := range sol.mate {
+		if w <= int64(v) {
+			continue
+		}
+		k := sol.mateedge[v]
+		result.Pairs = append(result.Pairs, Pair{First: int64(v), Second: w})
+		result.EdgeIndices = append(result.EdgeIndices, k)
+		result.Weights = append(result.Weights, edges[k].Weight)
+		result.TotalWeight = ar.Add(result.TotalWeight, edges[k].Weight)
+	}
+	result.Cardinality = len(result.Pairs)
+
+	return result
+}
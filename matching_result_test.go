@@ -0,0 +1,70 @@
+package mwm
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// TestMatchingResult - test that the result carries edge indices, weights and totals
+func TestMatchingResult(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{
+		{Node1: 0, Node2: 1, Weight: 10},
+		{Node1: 1, Node2: 2, Weight: 11},
+		{Node1: 2, Node2: 3, Weight: 12},
+		{Node1: 0, Node2: 3, Weight: 13},
+	}
+	result := matcher.MaxWeightMatchingResult(edges, false)
+	fmt.Println("Matching result:", result)
+
+	expectedPairs := []Pair{{First: 0, Second: 3}, {First: 1, Second: 2}}
+	if !reflect.DeepEqual(result.Pairs, expectedPairs) {
+		t.Errorf("Expected pairs %v, got %v", expectedPairs, result.Pairs)
+	}
+	expectedIndices := []int{3, 1}
+	if !reflect.DeepEqual(result.EdgeIndices, expectedIndices) {
+		t.Errorf("Expected edge indices %v, got %v", expectedIndices, result.EdgeIndices)
+	}
+	expectedWeights := []int64{13, 11}
+	if !reflect.DeepEqual(result.Weights, expectedWeights) {
+		t.Errorf("Expected weights %v, got %v", expectedWeights, result.Weights)
+	}
+	if result.TotalWeight != 24 {
+		t.Errorf("Expected total weight 24, got %d", result.TotalWeight)
+	}
+	if result.Cardinality != 2 {
+		t.Errorf("Expected cardinality 2, got %d", result.Cardinality)
+	}
+	expectedMate := []int64{3, 2, 1, 0}
+	if !reflect.DeepEqual(result.Mate, expectedMate) {
+		t.Errorf("Expected mate %v, got %v", expectedMate, result.Mate)
+	}
+}
+
+// TestMatchingResultParallelEdges - test that the heavier of two parallel edges is reported
+func TestMatchingResultParallelEdges(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{
+		{Node1: 0, Node2: 1, Weight: 3},
+		{Node1: 1, Node2: 0, Weight: 7},
+	}
+	result := matcher.MaxWeightMatchingResult(edges, false)
+	fmt.Println("Parallel edges:", result)
+
+	if !reflect.DeepEqual(result.EdgeIndices, []int{1}) {
+		t.Errorf("Expected edge indices [1], got %v", result.EdgeIndices)
+	}
+	if result.TotalWeight != 7 {
+		t.Errorf("Expected total weight 7, got %d", result.TotalWeight)
+	}
+}
+
+// TestMatchingResultEmpty - test the result for a graph without edges
+func TestMatchingResultEmpty(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	result := matcher.MaxWeightMatchingResult([]GraphEdge{}, false)
+	if result.Cardinality != 0 || result.TotalWeight != 0 || len(result.Pairs) != 0 || len(result.Mate) != 0 {
+		t.Errorf("Expected empty result, got %v", result)
+	}
+}
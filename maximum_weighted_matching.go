@@ -1,17 +1,23 @@
 package mwm
 
 import (
-	"fmt"
+	"context"
+	"log/slog"
 	"math"
+	"os"
+	"time"
 )
 
-// GraphEdge represents a graph edge with two nodes and weight
-type GraphEdge struct {
+// WeightedEdge represents a graph edge with two nodes and a weight of type W
+type WeightedEdge[W any] struct {
 	Node1  int64
 	Node2  int64
-	Weight int64
+	Weight W
 }
 
+// GraphEdge represents a graph edge with two nodes and weight
+type GraphEdge = WeightedEdge[int64]
+
 // Pair represents a pair of connected nodes
 type Pair struct {
 	First  int64
@@ -47,7 +53,27 @@ func GetIndex[T any](ind int, ofArr []T) int {
 
 // MaximumWeightedMatching object for the maximum weighted matching algorithm
 type MaximumWeightedMatching struct {
+	// DebugMode logs the algorithm steps to standard error when Logger is nil
 	DebugMode bool
+	// Logger, if not nil, receives the algorithm steps as structured events at debug level
+	Logger *slog.Logger
+	// Tracer, if not nil, is called at every algorithm step in addition to the logger
+	Tracer Tracer
+	// CollectStats reports counters and phase timings in the Stats field of the result
+	CollectStats bool
+	// Epsilon is the tolerance for floating-point weights; DefaultEpsilon is used when zero
+	Epsilon float64
+	// DetectBipartite hands bipartite graphs to the solver of MaxWeightBipartiteMatching, unless the
+	// run is warm started, traced or logged. Such results carry no VertexDuals and Blossoms
+	DetectBipartite bool
+	// Engine selects the implementation of the blossom algorithm
+	Engine Engine
+	// Scaling solves integer weights scale by scale from their most significant bits, warm starting
+	// every scale from the previous one with the scan engine. It exists only for experiments with
+	// warm starts and has no better bound than a single run. It is ignored with PriorityQueueEngine,
+	// for warm started, traced or logged runs, for runs that stop after a number of augmentations
+	// and for weights that are not integers
+	Scaling bool
 }
 
 // NewMaximumWeightedMatching creates a new instance of the algorithm
@@ -55,34 +81,277 @@ func NewMaximumWeightedMatching() *MaximumWeightedMatching {
 	return &MaximumWeightedMatching{DebugMode: false}
 }
 
-// MaxWeightMatching returns the maximum weighted matching as a list of pairs
+// tracer returns the observer of the algorithm steps for a run with context ctx, or nil if they are not observed
+func (mwm *MaximumWeightedMatching) tracer(ctx context.Context) Tracer {
+	var ts tracers
+	logger := mwm.Logger
+	if logger == nil && mwm.DebugMode {
+		logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+	if logger != nil && logger.Enabled(context.Background(), slog.LevelDebug) {
+		slogTracer := NewSlogTracer(logger)
+		slogTracer.Context = ctx
+		ts = append(ts, slogTracer)
+	}
+	if mwm.Tracer != nil {
+		ts = append(ts, mwm.Tracer)
+	}
+	switch len(ts) {
+	case 0:
+		return nil
+	case 1:
+		return ts[0]
+	}
+	return ts
+}
+
+// MaxWeightMatching returns the maximum weighted matching as a list of pairs.
+// Graphs whose edges all share one weight, positive or with maxCardinality set, are
+// solved by MaxCardinalityMatching, since every maximum cardinality matching is then optimal
 func (mwm *MaximumWeightedMatching) MaxWeightMatching(edges []GraphEdge, maxCardinality bool) []Pair {
-	mate := mwm.maxWeightMatchingInternal(edges, maxCardinality)
-	returnPairs := make([]Pair, 0)
-
-	for index, l := range mate {
-		if l != -1 {
-			found := false
-			for _, pair := range returnPairs {
-				if pair.First == l || pair.Second == l {
-					found = true
-					break
-				}
-			}
-			if !found {
-				returnPairs = append(returnPairs, Pair{First: int64(index), Second: l})
-			}
+	if mwm.uniformWeight(edges, maxCardinality) {
+		if result, err := mwm.MaxCardinalityMatching(edges); err == nil {
+			return result.Pairs
+		}
+	}
+	return mwm.MaxWeightMatchingResult(edges, maxCardinality).Pairs
+}
+
+// uniformWeight reports whether the maximum weight matching of edges is any maximum cardinality
+// matching and no tracer expects the events of the blossom algorithm
+func (mwm *MaximumWeightedMatching) uniformWeight(edges []GraphEdge, maxCardinality bool) bool {
+	if len(edges) == 0 || mwm.tracer(nil) != nil {
+		return false
+	}
+	for _, edge := range edges[1:] {
+		if edge.Weight != edges[0].Weight {
+			return false
 		}
 	}
+	return (edges[0].Weight > 0 || maxCardinality) && validateEdges(Int64Arithmetic{}, edges) == nil
+}
+
+// MaxWeightMatchingResult returns the maximum weighted matching together with
+// the matched edge indices, their weights and the mate array
+func (mwm *MaximumWeightedMatching) MaxWeightMatchingResult(edges []GraphEdge, maxCardinality bool) *MatchingResult {
+	result, err := mwm.solveInt64(edges, solveOptions{maxCardinality: maxCardinality})
+	if err != nil && result == nil {
+		panic(err)
+	}
+	return result
+}
+
+// MaxWeightMatchingE returns the maximum weighted matching like MaxWeightMatchingResult,
+// but validates the edges and reports violated internal invariants as errors instead of panicking.
+// If the total weight does not fit in int64, the result is returned together with ErrWeightOverflow
+func (mwm *MaximumWeightedMatching) MaxWeightMatchingE(edges []GraphEdge, maxCardinality bool) (*MatchingResult, error) {
+	if err := validateEdges(Int64Arithmetic{}, edges); err != nil {
+		return nil, err
+	}
+	return mwm.solveInt64(edges, solveOptions{maxCardinality: maxCardinality})
+}
+
+// MaxWeightMatchingWith returns the maximum weighted matching for weights of type W,
+// computing with the operations of ar. Like MaxWeightMatchingE it validates the edges
+// and reports violated internal invariants and overflows as errors
+func MaxWeightMatchingWith[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], maxCardinality bool) (*WeightedMatchingResult[W], error) {
+	if err := validateEdges(ar, edges); err != nil {
+		return nil, err
+	}
+	return solveWith(mwm, ar, edges, solveOptions{maxCardinality: maxCardinality})
+}
+
+// solveOptions controls a single run of maxWeightMatchingInternal
+type solveOptions struct {
+	// maxCardinality selects maximum cardinality mode
+	maxCardinality bool
+	// limitAugmentations stops the algorithm after augmentationLimit augmentations
+	limitAugmentations bool
+	augmentationLimit  int
+	// recordCurve records the weight of the matching after every augmentation
+	recordCurve bool
+	// ctx, if not nil, is checked for cancellation between stages and substages
+	ctx context.Context
+}
+
+// solveWith runs the algorithm with the operations of ar on validated edges
+func solveWith[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], opts solveOptions) (*WeightedMatchingResult[W], error) {
+	return solveFrom(mwm, ar, edges, opts, nil)
+}
+
+// solveFrom is solveWith starting from the matching and duals of start, or from scratch if start is nil
+func solveFrom[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], opts solveOptions, start *WarmStart[W]) (result *WeightedMatchingResult[W], err error) {
+	defer recoverFailure(&err)
+
+	sol := maxWeightMatchingInternal(mwm, ar, edges, opts, start)
+	return newMatchingResult(ar, edges, sol, opts.maxCardinality), sol.interrupted
+}
+
+// solution holds the final state of maxWeightMatchingInternal
+type solution[W any] struct {
+	// mate of every vertex, or -1
+	mate []int64
+	// index of the edge every vertex is matched by, or -1
+	mateedge []int
+	// dual variables of the vertices
+	vertexDuals []W
+	// blossoms remaining at termination
+	blossoms []WeightedBlossom[W]
+	// interrupted holds the context error if the algorithm was cancelled, in which case
+	// the matching is valid but vertexDuals and blossoms are nil
+	interrupted error
+	// stats holds the solver statistics when they were requested
+	stats *SolverStats
+	// curve holds the weight of the matching after 0, 1, 2, ... augmentations when requested
+	curve []W
+}
 
-	return returnPairs
+// maxWeightMatchingInternal main algorithm function, computing with weights of type W through ar
+func maxWeightMatchingInternal[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], opts solveOptions, start *WarmStart[W]) *solution[W] {
+	var e engine[W]
+	if sol := e.dispatch(mwm, ar, edges, opts, start); sol != nil {
+		return sol
+	}
+	return e.solution()
 }
 
-// maxWeightMatchingInternal main algorithm function
-func (mwm *MaximumWeightedMatching) maxWeightMatchingInternal(edges []GraphEdge, maxCardinality bool) []int64 {
-	if len(edges) == 0 {
-		return make([]int64, 0)
+// dispatch solves edges with the solver that the options of mwm select. A bipartite graph found by
+// DetectBipartite is solved by the bipartite solver, whose solution is returned. Otherwise the
+// blossom algorithm runs on e, with Scaling if it applies, and leaves its final state in e
+func (e *engine[W]) dispatch(mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], opts solveOptions, start *WarmStart[W]) *solution[W] {
+	if mwm.DetectBipartite && start == nil && mwm.tracer(opts.ctx) == nil && validateEdges(ar, edges) == nil {
+		if isLeft, err := bipartition(edges, nil); err == nil {
+			return maxWeightBipartite(mwm, ar, edges, isLeft, opts)
+		}
+	}
+
+	if mwm.Scaling && mwm.Engine != PriorityQueueEngine && start == nil && !opts.limitAugmentations && !opts.recordCurve && mwm.tracer(opts.ctx) == nil {
+		if e.runScaling(mwm, ar, edges, opts) {
+			return nil
+		}
+	}
+
+	e.init(mwm, ar, edges, opts)
+	if start != nil {
+		e.seed(start)
 	}
+	e.run()
+	return nil
+}
+
+// engine holds the state of the algorithm. All buffers are kept when the engine is
+// initialized for another graph, so a reused engine does not allocate in the steady state
+type engine[W any] struct {
+	mwm   *MaximumWeightedMatching
+	ar    Arithmetic[W]
+	edges []WeightedEdge[W]
+	opts  solveOptions
+
+	nvertex int
+	nedges  int
+
+	// endpoint[p] is the vertex of endpoint p; edge k has the endpoints 2*k and 2*k+1
+	endpoint []int64
+	// neighbend[neighbstart[v]:neighbstart[v+1]] lists the remote endpoints of the edges of vertex v
+	neighbstart []int
+	neighbend   []int
+	// adjacent, if not nil, replaces neighbend with a list per vertex that edits of a
+	// DynamicMatching change in place
+	adjacent [][]int
+	// freed lists the vertices that the edits of a DynamicMatching unmatched or whose dual they raised
+	freed []int
+
+	mate             []int64
+	mateedge         []int
+	label            []int
+	labelend         []int
+	inblossom        []int
+	blossomparent    []int
+	blossomchilds    [][]int
+	blossombase      []int
+	blossomendps     [][]int
+	bestedge         []int
+	blossombestedges [][]int
+	// bestedgesknown reports whether blossombestedges holds the least-slack edges of a blossom
+	bestedgesknown []bool
+	unusedblossoms []int
+	dualvar        []W
+	allowedge      []bool
+	queue          []int
+
+	// slacks64 shares the memory of dualvar and edges when the weights are computed with
+	// Int64Arithmetic, so that the scan engine compares slacks without dynamic calls
+	slacks64 int64Slacks
+
+	// Scratch buffers
+	leaves     []int
+	path       []int
+	bestedgeto []int
+
+	// maxCardinality is the mode of the current phase. A warm start in maximum cardinality
+	// mode runs without it until no free vertex has a dual above floor
+	maxCardinality bool
+	// After a warm start free vertices may have different duals. Only those above floor are
+	// roots, and a vertex of an S-blossom whose dual reaches floor is released from the matching
+	warm  bool
+	floor W
+
+	// pq points to queues while the priority queue engine runs, and is nil for the scan engine
+	pq     *dualQueues[W]
+	queues dualQueues[W]
+
+	// Current stage, reported when an internal invariant is violated
+	stage         int
+	augmentations int
+	// curve holds the matched weight after every augmentation if opts.recordCurve is set
+	curve []W
+	// interrupted holds the context error once cancelled has observed it
+	interrupted error
+	// Observer of the algorithm steps; callers check tracer != nil first
+	tracer Tracer
+	// Statistics are only collected when requested; lap charges the time since the previous lap to a phase
+	stats       *SolverStats
+	start, mark time.Time
+}
+
+// resize returns a slice of length n, reusing the memory of s when it is large enough
+func resize[T any](s []T, n int) []T {
+	if cap(s) < n {
+		grown := make([]T, n)
+		copy(grown, s[:cap(s)])
+		return grown
+	}
+	return s[:n]
+}
+
+// beginRun resets the counters, the observers and the statistics of a run
+func (e *engine[W]) beginRun() {
+	e.stage = -1
+	e.augmentations = 0
+	e.curve = e.curve[:0]
+	if e.opts.recordCurve {
+		e.curve = append(e.curve, e.ar.Zero())
+	}
+	e.interrupted = nil
+	e.tracer = e.mwm.tracer(e.opts.ctx)
+	e.stats = nil
+	if e.mwm.CollectStats {
+		e.stats = &SolverStats{}
+		e.start = time.Now()
+		e.mark = e.start
+	}
+}
+
+// init prepares the engine for a run on edges
+func (e *engine[W]) init(mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], opts solveOptions) {
+	e.mwm = mwm
+	e.ar = ar
+	e.edges = edges
+	e.opts = opts
+	e.maxCardinality = opts.maxCardinality
+	e.warm = false
+	e.floor = ar.Zero()
+	e.beginRun()
 
 	nedges := len(edges)
 	nvertex := 0
@@ -98,731 +367,983 @@ func (mwm *MaximumWeightedMatching) maxWeightMatchingInternal(edges []GraphEdge,
 			}
 		}
 	}
+	e.nvertex = nvertex
+	e.nedges = nedges
 
 	// Find the maximum weight
-	maxweight := int64(0)
+	maxweight := ar.Zero()
 	for _, edge := range edges {
-		if edge.Weight > maxweight {
+		if ar.Cmp(edge.Weight, maxweight) > 0 {
 			maxweight = edge.Weight
 		}
 	}
-	if maxweight < 0 {
-		maxweight = 0
+	if ar.Cmp(maxweight, ar.Zero()) < 0 {
+		maxweight = ar.Zero()
 	}
 
 	// Create list of edge endpoints
-	endpoint := make([]int64, 0, nedges*2)
-	for _, edge := range edges {
-		endpoint = append(endpoint, edge.Node1)
-		endpoint = append(endpoint, edge.Node2)
+	e.endpoint = resize(e.endpoint, nedges*2)
+	for k, edge := range edges {
+		e.endpoint[2*k] = edge.Node1
+		e.endpoint[2*k+1] = edge.Node2
 	}
 
-	// Create neighbor lists for each vertex
-	neighbend := make([][]int, nvertex)
-	for k := 0; k < nvertex; k++ {
-		neighbend[k] = make([]int, 0)
+	// Create neighbor lists for each vertex, keeping the order of the edges
+	e.neighbstart = resize(e.neighbstart, nvertex+1)
+	for v := range e.neighbstart {
+		e.neighbstart[v] = 0
 	}
-
+	for _, edge := range edges {
+		e.neighbstart[edge.Node1+1]++
+		e.neighbstart[edge.Node2+1]++
+	}
+	for v := 0; v < nvertex; v++ {
+		e.neighbstart[v+1] += e.neighbstart[v]
+	}
+	e.neighbend = resize(e.neighbend, nedges*2)
+	next := resize(e.path, nvertex)
+	copy(next, e.neighbstart[:nvertex])
 	for k, edge := range edges {
-		neighbend[edge.Node1] = append(neighbend[edge.Node1], 2*k+1)
-		neighbend[edge.Node2] = append(neighbend[edge.Node2], 2*k)
+		e.neighbend[next[edge.Node1]] = 2*k + 1
+		next[edge.Node1]++
+		e.neighbend[next[edge.Node2]] = 2 * k
+		next[edge.Node2]++
 	}
+	e.path = next[:0]
+	e.adjacent = nil
+	e.freed = e.freed[:0]
 
 	// Initialize arrays
-	mate := make([]int64, nvertex)
-	for i := 0; i < nvertex; i++ {
-		mate[i] = -1
+	e.mate = resize(e.mate, nvertex)
+	e.mateedge = resize(e.mateedge, nvertex)
+	e.inblossom = resize(e.inblossom, nvertex)
+	for v := 0; v < nvertex; v++ {
+		e.mate[v] = -1
+		e.mateedge[v] = -1
+		e.inblossom[v] = v
 	}
 
-	label := make([]int, nvertex*2)
-	labelend := make([]int, nvertex*2)
+	e.label = resize(e.label, nvertex*2)
+	e.labelend = resize(e.labelend, nvertex*2)
+	e.blossomparent = resize(e.blossomparent, nvertex*2)
+	e.blossomchilds = resize(e.blossomchilds, nvertex*2)
+	e.blossombase = resize(e.blossombase, nvertex*2)
+	e.blossomendps = resize(e.blossomendps, nvertex*2)
+	e.bestedge = resize(e.bestedge, nvertex*2)
+	e.blossombestedges = resize(e.blossombestedges, nvertex*2)
+	e.bestedgesknown = resize(e.bestedgesknown, nvertex*2)
+	e.dualvar = resize(e.dualvar, nvertex*2)
+	e.bestedgeto = resize(e.bestedgeto, nvertex*2)
+	e.unusedblossoms = e.unusedblossoms[:0]
 	for i := 0; i < nvertex*2; i++ {
-		labelend[i] = -1
+		e.label[i] = 0
+		e.labelend[i] = -1
+		e.blossomparent[i] = -1
+		e.blossomchilds[i] = e.blossomchilds[i][:0]
+		e.blossomendps[i] = e.blossomendps[i][:0]
+		e.bestedge[i] = -1
+		e.blossombestedges[i] = e.blossombestedges[i][:0]
+		e.bestedgesknown[i] = false
+		if i < nvertex {
+			e.blossombase[i] = i
+			e.dualvar[i] = maxweight
+		} else {
+			e.blossombase[i] = -1
+			e.dualvar[i] = ar.Zero()
+			e.unusedblossoms = append(e.unusedblossoms, i)
+		}
 	}
 
-	inblossom := make([]int, nvertex)
-	for i := 0; i < nvertex; i++ {
-		inblossom[i] = i
-	}
+	e.allowedge = resize(e.allowedge, nedges)
+	e.queue = e.queue[:0]
 
-	blossomparent := make([]int, nvertex*2)
-	for i := 0; i < nvertex*2; i++ {
-		blossomparent[i] = -1
-	}
+	e.bindSlacks()
 
-	blossomchilds := make([][]int, nvertex*2)
-	for i := 0; i < nvertex*2; i++ {
-		blossomchilds[i] = make([]int, 0)
+	e.pq = nil
+	if mwm.Engine == PriorityQueueEngine {
+		e.pq = &e.queues
+		e.pq.initQueues(ar, nvertex)
 	}
+}
 
-	blossombase := make([]int, nvertex*2)
-	for i := 0; i < nvertex; i++ {
-		blossombase[i] = i
-	}
-	for i := nvertex; i < nvertex*2; i++ {
-		blossombase[i] = -1
+// bindSlacks points slacks64 to dualvar and edges, which it shares when the weights are computed
+// with Int64Arithmetic
+func (e *engine[W]) bindSlacks() {
+	e.slacks64 = int64Slacks{}
+	if _, ok := any(e.ar).(Int64Arithmetic); ok {
+		e.slacks64 = int64Slacks{dualvar: any(e.dualvar).([]int64), edges: any(e.edges).([]GraphEdge)}
 	}
+}
 
-	blossomendps := make([][]int, nvertex*2)
-	for i := 0; i < nvertex*2; i++ {
-		blossomendps[i] = make([]int, 0)
-	}
+// lap charges the time since the previous lap to phase
+func (e *engine[W]) lap(phase *time.Duration) {
+	now := time.Now()
+	*phase += now.Sub(e.mark)
+	e.mark = now
+}
 
-	bestedge := make([]int, nvertex*2)
-	for i := 0; i < nvertex*2; i++ {
-		bestedge[i] = -1
+// cancelled reports whether the context of the run is done
+func (e *engine[W]) cancelled() bool {
+	if e.opts.ctx != nil && e.interrupted == nil {
+		e.interrupted = e.opts.ctx.Err()
 	}
+	return e.interrupted != nil
+}
 
-	blossombestedges := make([][]int, nvertex*2)
-	for i := 0; i < nvertex*2; i++ {
-		blossombestedges[i] = make([]int, 0)
-	}
+func (e *engine[W]) fail(step string) {
+	panic(&InvariantError{Stage: e.stage, Step: step})
+}
 
-	unusedblossoms := make([]int, 0, nvertex)
-	for i := nvertex; i < nvertex*2; i++ {
-		unusedblossoms = append(unusedblossoms, i)
-	}
+func (e *engine[W]) slack(k int) W {
+	edge := &e.edges[k]
+	return e.ar.Sub(e.ar.Add(e.dualvar[edge.Node1], e.dualvar[edge.Node2]), e.ar.Double(edge.Weight))
+}
 
-	dualvar := make([]int64, nvertex*2)
-	for i := 0; i < nvertex; i++ {
-		dualvar[i] = maxweight
+// lessSlack reports whether edge k has less slack than edge l in the scan engine, or l is -1
+func (e *engine[W]) lessSlack(k, l int) bool {
+	if s := &e.slacks64; s.dualvar != nil {
+		return s.less(k, l)
 	}
-	for i := nvertex; i < nvertex*2; i++ {
-		dualvar[i] = 0
+	return l == -1 || e.ar.Cmp(e.slack(k), e.slack(l)) < 0
+}
+
+// int64Slacks computes the slacks of the scan engine for Int64Arithmetic. Unlike the methods of
+// the generic engine, its methods are inlined, and so are the checked operations they call
+type int64Slacks struct {
+	dualvar []int64
+	edges   []GraphEdge
+}
+
+// slack returns the slack of edge k, with the overflow checks of Int64Arithmetic folded into one
+func (s *int64Slacks) slack(k int) int64 {
+	edge := &s.edges[k]
+	a, b, w := s.dualvar[edge.Node1], s.dualvar[edge.Node2], edge.Weight
+	sum, double := a+b, 2*w
+	slack := sum - double
+	if (a^sum)&(b^sum)|(w^double)|(sum^double)&(sum^slack) < 0 {
+		panic(ErrWeightOverflow)
 	}
+	return slack
+}
 
-	allowedge := make([]bool, nedges)
-	queue := make([]int, 0)
+// less reports whether edge k has less slack than edge l, or l is -1
+func (s *int64Slacks) less(k, l int) bool {
+	return l == -1 || s.slack(k) < s.slack(l)
+}
 
-	// Helper functions
-	slack := func(k int) int64 {
-		return dualvar[edges[k].Node1] + dualvar[edges[k].Node2] - 2*edges[k].Weight
+// neighbors returns the remote endpoints of the edges of vertex v
+func (e *engine[W]) neighbors(v int) []int {
+	if e.adjacent != nil {
+		return e.adjacent[v]
 	}
+	return e.neighbend[e.neighbstart[v]:e.neighbstart[v+1]]
+}
 
-	var blossomLeaves func(b int) []int
-	blossomLeaves = func(b int) []int {
-		if b < nvertex {
-			return []int{b}
-		}
-		leaves := make([]int, 0)
-		for _, t := range blossomchilds[b] {
-			if t < nvertex {
-				leaves = append(leaves, t)
-			} else {
-				leaves = append(leaves, blossomLeaves(t)...)
-			}
+// appendLeaves appends the vertices contained in blossom b to dst
+func (e *engine[W]) appendLeaves(dst []int, b int) []int {
+	if b < e.nvertex {
+		return append(dst, b)
+	}
+	for _, t := range e.blossomchilds[b] {
+		if t < e.nvertex {
+			dst = append(dst, t)
+		} else {
+			dst = e.appendLeaves(dst, t)
 		}
-		return leaves
 	}
+	return dst
+}
 
-	var assignLabel func(w, t, p int)
-	assignLabel = func(w, t, p int) {
-		if mwm.DebugMode {
-			fmt.Printf("DEBUG: assignLabel(%d,%d,%d)\n", w, t, p)
-		}
-		b := inblossom[w]
-		if !(label[w] == 0 && label[b] == 0) {
-			panic("assertion failed")
+func (e *engine[W]) assignLabel(w, t, p int) {
+	if e.tracer != nil {
+		e.tracer.OnAssignLabel(w, t, p)
+	}
+	b := e.inblossom[w]
+	if !(e.label[w] == 0 && e.label[b] == 0) {
+		e.fail("assignLabel: vertex already labeled")
+	}
+	e.label[w] = t
+	e.label[b] = t
+	e.labelend[w] = p
+	e.labelend[b] = p
+	e.bestedge[w] = -1
+	e.bestedge[b] = -1
+	if e.pq != nil {
+		root := w
+		if p != -1 {
+			root = e.pq.tree[e.endpoint[p]]
 		}
-		label[w] = t
-		label[b] = t
-		labelend[w] = p
-		labelend[b] = p
-		bestedge[w] = -1
-		bestedge[b] = -1
+		e.relabelDuals(b, t, root)
+	}
 
-		if t == 1 {
-			leaves := blossomLeaves(b)
-			queue = append(queue, leaves...)
-			if mwm.DebugMode {
-				fmt.Printf("DEBUG: PUSH %v\n", leaves)
-			}
-		} else if t == 2 {
-			base := blossombase[b]
-			if !(mate[base] >= 0) {
-				panic("assertion failed")
-			}
-			assignLabel(int(endpoint[mate[base]]), 1, int(mate[base])^1)
+	if t == 1 {
+		e.queue = e.appendLeaves(e.queue, b)
+	} else if t == 2 {
+		base := e.blossombase[b]
+		if !(e.mate[base] >= 0) {
+			e.fail("assignLabel: T-blossom base is unmatched")
 		}
+		e.assignLabel(int(e.endpoint[e.mate[base]]), 1, int(e.mate[base])^1)
 	}
+}
 
-	scanBlossom := func(parV, parW int) int {
-		if mwm.DebugMode {
-			fmt.Printf("DEBUG: scanBlossom(%d,%d)\n", parV, parW)
-		}
-		v, w := parV, parW
-		path := make([]int, 0)
-		base := -1
+func (e *engine[W]) scanBlossom(v, w int) int {
+	path := e.path[:0]
+	base := -1
 
-		for v != -1 || w != -1 {
-			var b int
-			if v != -1 {
-				b = inblossom[v]
-			} else {
-				b = inblossom[w]
-			}
+	for v != -1 || w != -1 {
+		var b int
+		if v != -1 {
+			b = e.inblossom[v]
+		} else {
+			b = e.inblossom[w]
+		}
 
-			if label[b]&4 != 0 {
-				base = blossombase[b]
-				break
-			}
+		if e.label[b]&4 != 0 {
+			base = e.blossombase[b]
+			break
+		}
 
-			if !(label[b] == 1) {
-				panic("assertion failed")
-			}
-			path = append(path, b)
-			label[b] = 5
+		if !(e.label[b] == 1) {
+			e.fail("scanBlossom: expected S-blossom")
+		}
+		path = append(path, b)
+		e.label[b] = 5
 
-			if !(labelend[b] == int(mate[blossombase[b]])) {
-				panic("assertion failed")
-			}
+		if !(e.labelend[b] == int(e.mate[e.blossombase[b]])) {
+			e.fail("scanBlossom: labelend does not match base mate")
+		}
 
-			if labelend[b] == -1 {
-				v = -1
-			} else {
-				v = int(endpoint[labelend[b]])
-				b = inblossom[v]
-				if !(label[b] == 2) {
-					panic("assertion failed")
-				}
-				if !(labelend[b] >= 0) {
-					panic("assertion failed")
-				}
-				v = int(endpoint[labelend[b]])
+		if e.labelend[b] == -1 {
+			v = -1
+		} else {
+			v = int(e.endpoint[e.labelend[b]])
+			b = e.inblossom[v]
+			if !(e.label[b] == 2) {
+				e.fail("scanBlossom: expected T-blossom")
 			}
-
-			if w != -1 {
-				v, w = w, v
+			if !(e.labelend[b] >= 0) {
+				e.fail("scanBlossom: T-blossom without labelend")
 			}
+			v = int(e.endpoint[e.labelend[b]])
 		}
 
-		for _, p := range path {
-			label[p] = 1
+		if w != -1 {
+			v, w = w, v
 		}
-
-		return base
 	}
 
-	var addBlossom func(base, k int)
-	var expandBlossom func(b int, endstage bool)
-	var augmentBlossom func(b, v int)
-	var augmentMatching func(k int)
+	for _, p := range path {
+		e.label[p] = 1
+	}
+	e.path = path
 
-	addBlossom = func(base, k int) {
-		edge := edges[k]
-		v := int(edge.Node1)
-		w := int(edge.Node2)
-		bb := inblossom[base]
-		bv := inblossom[v]
-		bw := inblossom[w]
+	return base
+}
 
-		b := unusedblossoms[len(unusedblossoms)-1]
-		unusedblossoms = unusedblossoms[:len(unusedblossoms)-1]
+func (e *engine[W]) addBlossom(base, k int) {
+	edge := e.edges[k]
+	v := int(edge.Node1)
+	w := int(edge.Node2)
+	bb := e.inblossom[base]
+	bv := e.inblossom[v]
+	bw := e.inblossom[w]
 
-		if mwm.DebugMode {
-			fmt.Printf("DEBUG: addBlossom(%d,%d) (v=%d w=%d) -> %d\n", base, k, v, w, b)
-		}
+	b := e.unusedblossoms[len(e.unusedblossoms)-1]
+	e.unusedblossoms = e.unusedblossoms[:len(e.unusedblossoms)-1]
 
-		blossombase[b] = base
-		blossomparent[b] = -1
-		blossomparent[bb] = b
+	if e.tracer != nil {
+		e.tracer.OnAddBlossom(b, base, k)
+	}
+	if e.stats != nil {
+		e.stats.BlossomsCreated++
+	}
 
-		//Make list of sub-blossoms and their interconnecting edge endpoints.
-		path := make([]int, 0)
-		endps := make([]int, 0)
+	e.blossombase[b] = base
+	e.blossomparent[b] = -1
+	e.blossomparent[bb] = b
 
-		// Build path to base
-		for bv != bb {
-			blossomparent[bv] = b
-			path = append(path, bv)
-			endps = append(endps, labelend[bv])
+	//Make list of sub-blossoms and their interconnecting edge endpoints.
+	path := e.blossomchilds[b][:0]
+	endps := e.blossomendps[b][:0]
 
-			if !(label[bv] == 2 || (label[bv] == 1 && labelend[bv] == int(mate[blossombase[bv]]))) {
-				panic("assertion failed")
-			}
-			if !(labelend[bv] >= 0) {
-				panic("assertion failed")
-			}
+	// Build path to base
+	for bv != bb {
+		e.blossomparent[bv] = b
+		path = append(path, bv)
+		endps = append(endps, e.labelend[bv])
 
-			v = int(endpoint[labelend[bv]])
-			bv = inblossom[v]
+		if !(e.label[bv] == 2 || (e.label[bv] == 1 && e.labelend[bv] == int(e.mate[e.blossombase[bv]]))) {
+			e.fail("addBlossom: invalid sub-blossom label")
+		}
+		if !(e.labelend[bv] >= 0) {
+			e.fail("addBlossom: sub-blossom without labelend")
 		}
 
-		path = append(path, bb)
+		v = int(e.endpoint[e.labelend[bv]])
+		bv = e.inblossom[v]
+	}
 
-		// Reverse path
-		for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
-			path[i], path[j] = path[j], path[i]
-		}
-		for i, j := 0, len(endps)-1; i < j; i, j = i+1, j-1 {
-			endps[i], endps[j] = endps[j], endps[i]
-		}
-		endps = append(endps, 2*k)
+	path = append(path, bb)
 
-		// Add second part of path
-		for bw != bb {
-			blossomparent[bw] = b
-			path = append(path, bw)
-			endps = append(endps, labelend[bw]^1)
+	// Reverse path
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	for i, j := 0, len(endps)-1; i < j; i, j = i+1, j-1 {
+		endps[i], endps[j] = endps[j], endps[i]
+	}
+	endps = append(endps, 2*k)
 
-			if !(label[bw] == 2 || (label[bw] == 1 && labelend[bw] == int(mate[blossombase[bw]]))) {
-				panic("assertion failed")
-			}
-			if !(labelend[bw] >= 0) {
-				panic("assertion failed")
-			}
+	// Add second part of path
+	for bw != bb {
+		e.blossomparent[bw] = b
+		path = append(path, bw)
+		endps = append(endps, e.labelend[bw]^1)
 
-			w = int(endpoint[labelend[bw]])
-			bw = inblossom[w]
+		if !(e.label[bw] == 2 || (e.label[bw] == 1 && e.labelend[bw] == int(e.mate[e.blossombase[bw]]))) {
+			e.fail("addBlossom: invalid sub-blossom label")
+		}
+		if !(e.labelend[bw] >= 0) {
+			e.fail("addBlossom: sub-blossom without labelend")
 		}
 
-		blossomchilds[b] = path
-		blossomendps[b] = endps
+		w = int(e.endpoint[e.labelend[bw]])
+		bw = e.inblossom[w]
+	}
 
-		// Compute label and labelend for new blossom
-		if label[bb] != 1 {
-			panic("assertion failed")
-		}
-		label[b] = 1
-		labelend[b] = labelend[bb]
-		//Set dual variable to zero.
-		dualvar[b] = 0
+	e.blossomchilds[b] = path
+	e.blossomendps[b] = endps
 
-		//Relabel vertices.
-		for _, vIns := range blossomLeaves(b) {
-			if label[inblossom[vIns]] == 2 {
-				queue = append(queue, vIns)
-			}
-			inblossom[vIns] = b
+	// Compute label and labelend for new blossom
+	if e.label[bb] != 1 {
+		e.fail("addBlossom: base is not an S-blossom")
+	}
+	e.label[b] = 1
+	e.labelend[b] = e.labelend[bb]
+	//Set dual variable to zero.
+	e.dualvar[b] = e.ar.Zero()
+
+	//Relabel vertices.
+	e.leaves = e.appendLeaves(e.leaves[:0], b)
+	for _, vIns := range e.leaves {
+		if e.label[e.inblossom[vIns]] == 2 {
+			e.queue = append(e.queue, vIns)
 		}
+		e.inblossom[vIns] = b
+	}
 
-		//Compute blossombestedges[b].
-		bestedgeto := make([]int, nvertex*2)
-		for i := 0; i < nvertex*2; i++ {
-			bestedgeto[i] = -1
+	if e.pq != nil {
+		// The sub-blossoms keep their duals as is, and the least-slack edges between S-blossoms
+		// are found in the queue of delta type 3 instead of blossombestedges
+		for _, vIns := range e.leaves {
+			e.setVertexMode(vIns, 1)
 		}
-
 		for _, it := range path {
-			var nblists [][]int
-			if blossombestedges[it] != nil && len(blossombestedges[it]) == 0 {
-				nblists = [][]int{blossombestedges[it]}
-			} else {
-				nblists = make([][]int, 0)
-				for i, blV := range blossomLeaves(it) {
-					nblists = append(nblists, make([]int, 0))
-					for _, neighBlV := range neighbend[blV] {
-						nblists[i] = append(nblists[i], IntFloorDiv(neighBlV, 2))
-					}
-				}
+			if it >= e.nvertex {
+				e.setBlossomMode(it, 0)
 			}
+			e.bestedge[it] = -1
+		}
+		e.setBlossomMode(b, 1)
+		e.bestedge[b] = -1
+		return
+	}
 
-			for _, nblist := range nblists {
-				for _, intNbList := range nblist {
-					edgeNb := edges[intNbList]
-					i := edgeNb.Node1
-					j := edgeNb.Node2
-					if inblossom[j] == b {
-						i, j = j, i
-					}
-					bj := inblossom[j]
-					if bj != b && label[bj] == 1 && (bestedgeto[bj] == -1 || slack(intNbList) < slack(bestedgeto[bj])) {
-						bestedgeto[bj] = intNbList
-					}
+	//Compute blossombestedges[b].
+	for i := range e.bestedgeto {
+		e.bestedgeto[i] = -1
+	}
+
+	for _, it := range path {
+		if e.bestedgesknown[it] {
+			for _, nb := range e.blossombestedges[it] {
+				e.considerBestEdge(b, nb)
+			}
+		} else {
+			e.leaves = e.appendLeaves(e.leaves[:0], it)
+			for _, blV := range e.leaves {
+				for _, neighBlV := range e.neighbors(blV) {
+					e.considerBestEdge(b, IntFloorDiv(neighBlV, 2))
 				}
 			}
-			blossombestedges[it] = make([]int, 0)
-			bestedge[it] = -1
 		}
+		e.blossombestedges[it] = e.blossombestedges[it][:0]
+		e.bestedgesknown[it] = false
+		e.bestedge[it] = -1
+	}
 
-		blossombestedges[b] = make([]int, 0, len(bestedgeto))
-		for _, val := range bestedgeto {
-			if val != -1 {
-				blossombestedges[b] = append(blossombestedges[b], val)
-			}
+	bestedges := e.blossombestedges[b][:0]
+	for _, val := range e.bestedgeto {
+		if val != -1 {
+			bestedges = append(bestedges, val)
 		}
-		bestedge[b] = -1
+	}
+	e.blossombestedges[b] = bestedges
+	e.bestedgesknown[b] = true
+	e.bestedge[b] = -1
 
-		for _, it := range blossombestedges[b] {
-			if bestedge[b] == -1 || slack(it) < slack(bestedge[b]) {
-				bestedge[b] = it
-			}
+	for _, it := range bestedges {
+		if e.lessSlack(it, e.bestedge[b]) {
+			e.bestedge[b] = it
 		}
+	}
+}
 
-		if mwm.DebugMode {
-			fmt.Printf("DEBUG: blossomchilds[%d]=%v\n", b, blossomchilds[b])
-		}
+// considerBestEdge records edge k as least-slack edge from the new blossom b to an S-blossom
+func (e *engine[W]) considerBestEdge(b, k int) {
+	edge := &e.edges[k]
+	j := edge.Node2
+	if e.inblossom[j] == b {
+		j = edge.Node1
+	}
+	bj := e.inblossom[j]
+	if bj != b && e.label[bj] == 1 && e.lessSlack(k, e.bestedgeto[bj]) {
+		e.bestedgeto[bj] = k
 	}
+}
 
-	expandBlossom = func(b int, endstage bool) {
-		if mwm.DebugMode {
-			fmt.Printf("DEBUG: expandBlossom(%d,%t) %v\n", b, endstage, blossomchilds[b])
-		}
+func (e *engine[W]) expandBlossom(b int, endstage bool) {
+	if e.tracer != nil {
+		e.tracer.OnExpandBlossom(b, endstage)
+	}
+	if e.stats != nil {
+		e.stats.BlossomsExpanded++
+	}
 
-		for _, s := range blossomchilds[b] {
-			blossomparent[s] = -1
-			if s < nvertex {
-				inblossom[s] = s
-			} else if endstage && dualvar[s] == 0 {
-				expandBlossom(s, endstage)
-			} else {
-				leaves := blossomLeaves(s)
-				for _, v := range leaves {
-					inblossom[v] = s
-				}
+	for _, s := range e.blossomchilds[b] {
+		e.blossomparent[s] = -1
+		if s < e.nvertex {
+			e.inblossom[s] = s
+		} else if endstage && e.ar.Cmp(e.dualvar[s], e.ar.Zero()) == 0 {
+			e.expandBlossom(s, endstage)
+		} else {
+			e.leaves = e.appendLeaves(e.leaves[:0], s)
+			for _, v := range e.leaves {
+				e.inblossom[v] = s
 			}
 		}
+	}
 
-		// Assign labels
-		if !endstage && label[b] == 2 {
-			if !(labelend[b] >= 0) {
-				panic("assertion failed")
-			}
+	// Assign labels
+	if !endstage && e.label[b] == 2 {
+		if !(e.labelend[b] >= 0) {
+			e.fail("expandBlossom: T-blossom without labelend")
+		}
+		childs := e.blossomchilds[b]
+		endps := e.blossomendps[b]
 
-			// Find starting position in path
-			entrychild := inblossom[int(endpoint[labelend[b]^1])]
-			j := indexOf(blossomchilds[b], entrychild)
-			var jstep int
-			var endptrick int
+		// Find starting position in path
+		entrychild := e.inblossom[int(e.endpoint[e.labelend[b]^1])]
+		j := indexOf(childs, entrychild)
+		var jstep int
+		var endptrick int
 
-			if j&1 != 0 {
-				j -= len(blossomchilds[b])
-				jstep = 1
-				endptrick = 0
-			} else {
-				jstep = -1
-				endptrick = 1
-			}
+		if j&1 != 0 {
+			j -= len(childs)
+			jstep = 1
+			endptrick = 0
+		} else {
+			jstep = -1
+			endptrick = 1
+		}
 
-			p := labelend[b]
+		p := e.labelend[b]
 
-			for j != 0 {
-				//Relabel the T-sub-blossom.
-				label[endpoint[GetIndex(p^1, endpoint)]] = 0
+		for j != 0 {
+			//Relabel the T-sub-blossom.
+			e.label[e.endpoint[GetIndex(p^1, e.endpoint)]] = 0
 
-				var innerLabelIndex = GetIndex(j-endptrick, blossomendps[b])
-				label[endpoint[GetIndex(blossomendps[b][innerLabelIndex]^endptrick^1, endpoint)]] = 0
-				assignLabel(int(endpoint[GetIndex(p^1, endpoint)]), 2, p)
-				allowedge[IntFloorDiv(blossomendps[b][innerLabelIndex], 2)] = true
-				j += jstep
-				p = blossomendps[b][GetIndex(j-endptrick, blossomendps[b])] ^ endptrick
-				allowedge[IntFloorDiv(p, 2)] = true
+			var innerLabelIndex = GetIndex(j-endptrick, endps)
+			e.label[e.endpoint[GetIndex(endps[innerLabelIndex]^endptrick^1, e.endpoint)]] = 0
+			e.assignLabel(int(e.endpoint[GetIndex(p^1, e.endpoint)]), 2, p)
+			e.allowedge[IntFloorDiv(endps[innerLabelIndex], 2)] = true
+			j += jstep
+			p = endps[GetIndex(j-endptrick, endps)] ^ endptrick
+			e.allowedge[IntFloorDiv(p, 2)] = true
+			j += jstep
+		}
+
+		bv := childs[GetIndex(j, childs)]
+		e.label[bv] = 2
+		e.label[e.endpoint[p^1]] = e.label[bv]
+		e.labelend[e.endpoint[p^1]] = p
+		e.labelend[bv] = p
+		e.bestedge[bv] = -1
+		j += jstep
+
+		for childs[GetIndex(j, childs)] != entrychild {
+			bv = childs[GetIndex(j, childs)]
+			if e.label[bv] == 1 {
 				j += jstep
+				continue
 			}
 
-			bv := blossomchilds[b][j]
-			label[bv] = 2
-			label[endpoint[p^1]] = label[bv]
-			labelend[endpoint[p^1]] = p
-			labelend[bv] = p
-			bestedge[bv] = -1
-			j += jstep
-
-			for blossomchilds[b][GetIndex(j, blossomchilds[b])] != entrychild {
-				bv = blossomchilds[b][GetIndex(j, blossomchilds[b])]
-				if label[bv] == 1 {
-					j += jstep
-					continue
-				}
-				if mwm.DebugMode {
-					fmt.Printf("DEBUU: %v\n", blossomLeaves(bv))
+			var v int
+			e.leaves = e.appendLeaves(e.leaves[:0], bv)
+			for _, v = range e.leaves {
+				if e.label[v] != 0 {
+					break
 				}
+			}
 
-				var v int
-				for _, v = range blossomLeaves(bv) {
-					if label[v] != 0 {
-						break
-					}
+			if e.label[v] != 0 {
+				if e.label[v] != 2 {
+					e.fail("expandBlossom: expected T-vertex")
 				}
-
-				if mwm.DebugMode {
-					fmt.Printf("LABELON = %v\n", label[v])
+				if e.inblossom[v] != bv {
+					e.fail("expandBlossom: vertex outside sub-blossom")
 				}
+				e.label[v] = 0
+				e.label[e.endpoint[e.mate[e.blossombase[bv]]]] = 0
+				e.assignLabel(v, 2, e.labelend[v])
+			}
+			j += jstep
+		}
+	}
 
-				if label[v] != 0 {
-					if label[v] != 2 {
-						panic("assertion failed")
-					}
-					if inblossom[v] != bv {
-						panic("assertion failed")
-					}
-					label[v] = 0
-					label[endpoint[mate[blossombase[bv]]]] = 0
-					assignLabel(v, 2, labelend[v])
+	if e.pq != nil {
+		if !endstage {
+			for _, s := range e.blossomchilds[b] {
+				root := -1
+				if e.label[s] != 0 {
+					root = e.pq.tree[e.blossombase[s]]
 				}
-				j += jstep
+				e.relabelDuals(s, e.label[s], root)
 			}
 		}
-
-		// Remove blossom from the list of available blossoms
-		label[b] = -1
-		labelend[b] = -1
-		blossomchilds[b] = nil
-		blossomendps[b] = nil
-		blossombase[b] = -1
-		bestedge[b] = -1
-		blossombestedges[b] = nil
-		unusedblossoms = append(unusedblossoms, b)
+		e.setBlossomMode(b, 0)
 	}
 
-	augmentBlossom = func(b, v int) {
-		if mwm.DebugMode {
-			fmt.Printf("DEBUG: augmentBlossom(%d,%d)\n", b, v)
+	// Remove blossom from the list of available blossoms
+	e.label[b] = -1
+	e.labelend[b] = -1
+	e.blossomchilds[b] = e.blossomchilds[b][:0]
+	e.blossomendps[b] = e.blossomendps[b][:0]
+	e.blossombase[b] = -1
+	e.bestedge[b] = -1
+	e.blossombestedges[b] = e.blossombestedges[b][:0]
+	e.bestedgesknown[b] = false
+	e.unusedblossoms = append(e.unusedblossoms, b)
+}
+
+// rotate moves the first i elements of s to its end in place
+func rotate(s []int, i int) {
+	reverse := func(s []int) {
+		for a, b := 0, len(s)-1; a < b; a, b = a+1, b-1 {
+			s[a], s[b] = s[b], s[a]
 		}
+	}
+	reverse(s[:i])
+	reverse(s[i:])
+	reverse(s)
+}
 
-		// Find v in child blossoms
-		t := v
-		var jstep int
-		var endptrick int
+func (e *engine[W]) augmentBlossom(b, v int) {
 
-		for blossomparent[t] != b {
-			t = blossomparent[t]
-		}
+	// Find v in child blossoms
+	t := v
+	var jstep int
+	var endptrick int
+
+	for e.blossomparent[t] != b {
+		t = e.blossomparent[t]
+	}
+
+	if t >= e.nvertex {
+		e.augmentBlossom(t, v)
+	}
+
+	childs := e.blossomchilds[b]
+	endps := e.blossomendps[b]
+	i := indexOf(childs, t)
+	j := i
+
+	if i&1 != 0 {
+		j -= len(childs)
+		jstep = 1
+		endptrick = 0
+	} else {
+		jstep = -1
+		endptrick = 1
+	}
 
-		if t >= nvertex {
-			augmentBlossom(t, v)
+	for j != 0 {
+		j += jstep
+		t = childs[GetIndex(j, childs)]
+		p := endps[GetIndex(j-endptrick, endps)] ^ endptrick
+
+		if t >= e.nvertex {
+			e.augmentBlossom(t, int(e.endpoint[p]))
 		}
+		j += jstep
 
-		i := indexOf(blossomchilds[b], t)
-		j := indexOf(blossomchilds[b], t)
+		t = childs[GetIndex(j, childs)]
 
-		if i&1 != 0 {
-			j -= len(blossomchilds[b])
-			jstep = 1
-			endptrick = 0
-		} else {
-			jstep = -1
-			endptrick = 1
+		if t >= e.nvertex {
+			e.augmentBlossom(t, int(e.endpoint[p^1]))
 		}
 
-		for j != 0 {
-			j += jstep
-			t = blossomchilds[b][GetIndex(j, blossomchilds[b])]
-			p := blossomendps[b][GetIndex(j-endptrick, blossomendps[b])] ^ endptrick
+		e.mate[e.endpoint[GetIndex(p, e.endpoint)]] = int64(p ^ 1)
+		e.mate[e.endpoint[GetIndex(p^1, e.endpoint)]] = int64(p)
 
-			if t >= nvertex {
-				augmentBlossom(t, int(endpoint[p]))
-			}
-			j += jstep
+	}
+	rotate(childs, i)
+	rotate(endps, i)
+	e.blossombase[b] = e.blossombase[childs[0]]
+	if e.blossombase[b] != v {
+		e.fail("augmentBlossom: base mismatch after rotation")
+	}
+}
 
-			t = blossomchilds[b][GetIndex(j, blossomchilds[b])]
+func (e *engine[W]) augmentMatching(k int) {
+	edge := e.edges[k]
+	v := int(edge.Node1)
+	w := int(edge.Node2)
 
-			if t >= nvertex {
-				augmentBlossom(t, int(endpoint[p^1]))
-			}
+	if e.tracer != nil {
+		e.tracer.OnAugment(k, v, w)
+	}
+	if e.pq != nil {
+		e.pq.joined = [2]int{e.pq.tree[v], e.pq.tree[w]}
+	}
 
-			mate[endpoint[GetIndex(p, endpoint)]] = int64(p ^ 1)
-			mate[endpoint[GetIndex(p^1, endpoint)]] = int64(p)
+	e.augmentPath(v, 2*k+1)
+	e.augmentPath(w, 2*k)
+}
 
-			if mwm.DebugMode {
-				fmt.Printf("DEBUG: PAIR %d %d (k = %d)\n", endpoint[p], endpoint[p^1], IntFloorDiv(p, 2))
+// augmentPath matches vertex s through endpoint p, or leaves it free if p is -1,
+// and flips the alternating path from the blossom of s to the root of its tree
+func (e *engine[W]) augmentPath(s, p int) {
+	for {
+		bs := e.inblossom[s]
+		if e.label[bs] == 0 {
+			// After a warm start the path may end in a free blossom that is no root
+			if !(e.warm && e.mate[e.blossombase[bs]] == -1) {
+				e.fail("augmentMatching: expected S-blossom")
+			}
+			if bs >= e.nvertex {
+				e.augmentBlossom(bs, s)
 			}
+			e.mate[s] = int64(p)
+			break
 		}
-		blossomchilds[b] = append(blossomchilds[b][i:], blossomchilds[b][:i]...)
-		blossomendps[b] = append(blossomendps[b][i:], blossomendps[b][:i]...)
-		blossombase[b] = blossombase[blossomchilds[b][0]]
-		if blossombase[b] != v {
-			panic("1")
+		if !(e.label[bs] == 1) {
+			e.fail("augmentMatching: expected S-blossom")
 		}
-	}
-
-	augmentMatching = func(k int) {
-		edge := edges[k]
-		v := int(edge.Node1)
-		w := int(edge.Node2)
-
-		if mwm.DebugMode {
-			fmt.Printf("DEBUG: augmentMatching(%d) (v=%d w=%d)\n", k, v, w)
-			fmt.Printf("DEBUG: PAIR %d %d (k=%d)\n", v, w, k)
+		if !(e.labelend[bs] == int(e.mate[e.blossombase[bs]])) {
+			e.fail("augmentMatching: labelend does not match base mate")
 		}
 
-		listPair := []struct{ s, p int }{{v, 2*k + 1}, {w, 2 * k}}
+		if bs >= e.nvertex {
+			e.augmentBlossom(bs, s)
+		}
 
-		for _, pair := range listPair {
-			s, p := pair.s, pair.p
+		e.mate[s] = int64(p)
 
-			for {
-				bs := inblossom[s]
-				if !(label[bs] == 1) {
-					panic("assertion failed")
-				}
-				if !(labelend[bs] == int(mate[blossombase[bs]])) {
-					panic("assertion failed")
-				}
+		if e.labelend[bs] == -1 {
+			break
+		}
 
-				if bs >= nvertex {
-					augmentBlossom(bs, s)
-				}
+		t := int(e.endpoint[e.labelend[bs]])
+		bt := e.inblossom[t]
+		if !(e.label[bt] == 2) {
+			e.fail("augmentMatching: expected T-blossom")
+		}
+		if !(e.labelend[bt] >= 0) {
+			e.fail("augmentMatching: T-blossom without labelend")
+		}
 
-				mate[s] = int64(p)
+		s = int(e.endpoint[e.labelend[bt]])
+		j := int(e.endpoint[e.labelend[bt]^1])
 
-				if labelend[bs] == -1 {
-					break
-				}
+		if !(e.blossombase[bt] == t) {
+			e.fail("augmentMatching: T-blossom base mismatch")
+		}
 
-				t := int(endpoint[labelend[bs]])
-				bt := inblossom[t]
-				if !(label[bt] == 2) {
-					panic("assertion failed")
-				}
-				if !(labelend[bt] >= 0) {
-					panic("assertion failed")
-				}
+		if bt >= e.nvertex {
+			e.augmentBlossom(bt, j)
+		}
 
-				s = int(endpoint[labelend[bt]])
-				j := int(endpoint[labelend[bt]^1])
+		e.mate[j] = int64(e.labelend[bt])
+		p = e.labelend[bt] ^ 1
+	}
+}
 
-				if !(blossombase[bt] == t) {
-					panic("assertion failed")
-				}
+// isRoot reports whether the free vertex v is labeled S at the start of a stage
+func (e *engine[W]) isRoot(v int) bool {
+	return !e.warm || e.maxCardinality || e.ar.Cmp(e.dualvar[v], e.floor) > 0
+}
 
-				if bt >= nvertex {
-					augmentBlossom(bt, j)
-				}
+// hasRoot reports whether a free vertex is labeled S at the start of a stage
+func (e *engine[W]) hasRoot() bool {
+	for v := 0; v < e.nvertex; v++ {
+		if e.mate[v] == -1 && e.isRoot(v) {
+			return true
+		}
+	}
+	return false
+}
 
-				mate[j] = int64(labelend[bt])
-				p = labelend[bt] ^ 1
+// labelRoots clears all labels of the previous stage and labels the roots of the new stage S
+func (e *engine[W]) labelRoots() {
+	nvertex := e.nvertex
 
-				if mwm.DebugMode {
-					fmt.Printf("DEBUG: PAIR %d %d (k=%d)\n", s, t, p/2)
-				}
-			}
-		}
+	// Reset labels
+	for i := 0; i < nvertex*2; i++ {
+		e.label[i] = 0
 	}
 
-	// Main algorithm loop
-	mainLoop := func() []int64 {
-		for t := 0; t < nvertex; t++ {
-			if mwm.DebugMode {
-				fmt.Printf("DEBUG: STAGE %d\n", t)
-			}
+	// Reset best edges
+	for i := 0; i < nvertex*2; i++ {
+		e.bestedge[i] = -1
+	}
 
-			// Reset labels
-			for i := 0; i < nvertex*2; i++ {
-				label[i] = 0
-			}
+	// Reset blossom best edges
+	for i := 0; i < nvertex*2; i++ {
+		e.blossombestedges[i] = e.blossombestedges[i][:0]
+		e.bestedgesknown[i] = false
+	}
 
-			// Reset best edges
-			for i := 0; i < nvertex*2; i++ {
-				bestedge[i] = -1
-			}
+	// Reset allowed edges
+	for i := 0; i < e.nedges; i++ {
+		e.allowedge[i] = false
+	}
 
-			// Reset blossom best edges
-			for i := 0; i < nvertex*2; i++ {
-				blossombestedges[i] = make([]int, 0)
-			}
+	e.queue = e.queue[:0]
 
-			// Reset allowed edges
-			for i := 0; i < nedges; i++ {
-				allowedge[i] = false
+	// Assign labels to unmatched vertices
+	for v := 0; v < nvertex; v++ {
+		if e.mate[v] == -1 && e.label[e.inblossom[v]] == 0 && e.isRoot(v) {
+			e.assignLabel(v, 1, -1)
+			if e.pq != nil && e.pq.root == -1 {
+				e.pq.root = v
 			}
+		}
+	}
+	if e.pq != nil {
+		e.pq.fresh = false
+	}
+}
 
-			queue = make([]int, 0)
+// scanVertex scans the edges of the S-vertex v and reports whether it augmented the matching
+func (e *engine[W]) scanVertex(v int) bool {
+	// The priority queue engine queues only the least-slack edge from v to another S-blossom
+	best := -1
+	var bestslack W
 
-			// Assign labels to unmatched vertices
-			for v := 0; v < nvertex; v++ {
-				if mate[v] == -1 && label[inblossom[v]] == 0 {
-					assignLabel(v, 1, -1)
-				}
+	for _, p := range e.neighbors(v) {
+		augmented, between, kslack := e.scanEdge(v, p)
+		if augmented {
+			return true
+		}
+		if !between {
+			continue
+		}
+		if e.pq != nil {
+			if best == -1 || e.ar.Cmp(kslack, bestslack) < 0 {
+				best, bestslack = p^1, kslack
 			}
+		} else if b := e.inblossom[v]; e.lessSlack(p/2, e.bestedge[b]) {
+			e.bestedge[b] = p / 2
+		}
+	}
+	if best != -1 {
+		e.queueEdge(best, bestslack)
+	}
+	return false
+}
 
-			augmented := false
+// scanEdge scans the edge from the S-vertex v to the remote endpoint p and reports whether it
+// augmented the matching. For an edge to another S-blossom that is not tight, it reports between
+// and, in the priority queue engine, the slack of the edge
+func (e *engine[W]) scanEdge(v, p int) (augmented, between bool, kslack W) {
+	ar := e.ar
+	label := e.label
+	inblossom := e.inblossom
+	k := IntFloorDiv(p, 2)
+	w := int(e.endpoint[p])
+
+	if inblossom[v] == inblossom[w] {
+		return false, false, kslack
+	}
 
-			for {
-				if mwm.DebugMode {
-					fmt.Println("DEBUG: SUBSTAGE")
-				}
+	if !e.allowedge[k] {
+		if e.pq != nil {
+			kslack = e.queuedSlack(k)
+			e.allowedge[k] = ar.Cmp(kslack, ar.Zero()) <= 0
+		} else if s := &e.slacks64; s.dualvar != nil {
+			e.allowedge[k] = s.slack(k) <= 0
+		} else {
+			e.allowedge[k] = ar.Cmp(e.slack(k), ar.Zero()) <= 0
+		}
+	}
 
-				// Process queue
-				for len(queue) > 0 && !augmented {
-					v := queue[len(queue)-1]
-					queue = queue[:len(queue)-1]
+	if e.allowedge[k] {
+		if label[inblossom[w]] == 0 {
+			if e.mate[e.blossombase[inblossom[w]]] == -1 {
+				// A free vertex that is no root ends an augmenting path
+				e.augmentMatching(k)
+				return true, false, kslack
+			}
+			e.assignLabel(w, 2, p^1)
+		} else if label[inblossom[w]] == 1 {
+			base := e.scanBlossom(v, w)
+			if base < 0 {
+				e.augmentMatching(k)
+				return true, false, kslack
+			}
+			e.addBlossom(base, k)
+		} else if label[w] == 0 {
+			if !(label[inblossom[w]] == 2) {
+				e.fail("scan: expected T-blossom")
+			}
+			label[w] = 2
+			e.labelend[w] = p ^ 1
+		}
+	} else if label[inblossom[w]] == 1 {
+		return false, true, kslack
+	} else if label[w] == 0 {
+		if e.pq != nil {
+			e.offerBestEdge(w, k, kslack)
+		} else if e.lessSlack(k, e.bestedge[w]) {
+			e.bestedge[w] = k
+		}
+	}
+	return false, false, kslack
+}
 
-					if mwm.DebugMode {
-						fmt.Printf("DEBUG: POP v=%d\n", v)
-					}
+// run executes the main algorithm loop and leaves the matching in mate and mateedge
+func (e *engine[W]) run() {
+	ar := e.ar
+	nvertex := e.nvertex
+	stats := e.stats
+	tracer := e.tracer
+	label := e.label
+	inblossom := e.inblossom
+	bestedge := e.bestedge
+	dualvar := e.dualvar
+	allowedge := e.allowedge
+	mate := e.mate
+
+	if stats != nil {
+		e.lap(&stats.SetupTime)
+	}
 
-					if !(label[inblossom[v]] == 1) {
-						panic("assertion failed")
-					}
+	// Every stage but the last augments the matching or, after a warm start, releases a vertex
+	for t := 0; t < 2*nvertex; t++ {
+		// Stop early once the requested number of edges is matched
+		if e.opts.limitAugmentations && e.augmentations >= e.opts.augmentationLimit {
+			break
+		}
+		if e.cancelled() {
+			break
+		}
+		// Once all free vertices of a warm start have reached the floor, the matching is optimal,
+		// and they share the smallest dual as required by maximum cardinality mode
+		if e.warm && !e.maxCardinality && !e.hasRoot() {
+			if !e.opts.maxCardinality {
+				break
+			}
+			e.maxCardinality = true
+			// The free vertices at the floor become roots, so the priority queue engine grows all trees anew
+			if e.pq != nil {
+				e.settleDuals()
+				e.pq.initQueues(ar, nvertex)
+			}
+		}
+		e.stage = t
+		if tracer != nil {
+			tracer.OnStage(t)
+		}
+		if stats != nil {
+			stats.Stages++
+		}
 
-					// Check all neighbors
-					for _, p := range neighbend[v] {
-						k := IntFloorDiv(p, 2)
-						w := int(endpoint[p])
+		// The priority queue engine keeps the trees that the last augmentation left intact
+		if e.pq == nil || e.pq.fresh {
+			e.labelRoots()
+		}
 
-						if inblossom[v] == inblossom[w] {
-							continue
-						}
+		augmented := false
+		released := false
+		if e.pq != nil {
+			augmented = e.scanReleased()
+		}
 
-						var kslack int64
-						if !allowedge[k] {
-							kslack = slack(k)
-							if kslack <= 0 {
-								allowedge[k] = true
-							}
-						}
+		for {
+			if e.cancelled() {
+				break
+			}
+			if tracer != nil {
+				tracer.OnSubstage()
+			}
+			if stats != nil {
+				stats.Substages++
+			}
 
-						if allowedge[k] {
-							if label[inblossom[w]] == 0 {
-								assignLabel(w, 2, p^1)
-							} else if label[inblossom[w]] == 1 {
-								base := scanBlossom(v, w)
-								if base >= 0 {
-									addBlossom(base, k)
-								} else {
-									augmentMatching(k)
-									augmented = true
-									break
-								}
-							} else if label[w] == 0 {
-								if !(label[inblossom[w]] == 2) {
-									panic("assertion failed")
-								}
-								label[w] = 2
-								labelend[w] = p ^ 1
-							}
-						} else if label[inblossom[w]] == 1 {
-							b := inblossom[v]
-							if bestedge[b] == -1 || kslack < slack(bestedge[b]) {
-								bestedge[b] = k
-							}
-						} else if label[w] == 0 {
-							if bestedge[w] == -1 || kslack < slack(bestedge[w]) {
-								bestedge[w] = k
-							}
-						}
-					}
+			// Process queue
+			for len(e.queue) > 0 && !augmented {
+				v := e.queue[len(e.queue)-1]
+				e.queue = e.queue[:len(e.queue)-1]
 
-					if augmented {
-						break
-					}
+				if !(label[inblossom[v]] == 1) {
+					e.fail("scan: popped vertex is not in an S-blossom")
 				}
 
-				if augmented {
-					break
+				if e.scanVertex(v) {
+					augmented = true
 				}
+			}
 
-				// Calculate delta
-				deltatype := -1
-				delta := int64(0)
-				deltaedge := 0
-				deltablossom := 0
+			if augmented {
+				break
+			}
 
-				if !maxCardinality {
-					deltatype = 1
-					delta = math.MaxInt64
+			if stats != nil {
+				e.lap(&stats.ScanTime)
+			}
+
+			// Calculate delta
+			deltatype := -1
+			delta := ar.Zero()
+			deltaedge := 0
+			deltablossom := 0
+			deltavertex := -1
+
+			if e.warm && !e.maxCardinality {
+				// Delta type 1 is the distance of the S-vertex closest to the floor, preferring a root
+				deltatype = 1
+				if e.pq != nil {
+					deltavertex = e.floorVertex()
+				} else {
 					for v := 0; v < nvertex; v++ {
-						if dualvar[v] < delta {
-							delta = dualvar[v]
+						if label[inblossom[v]] != 1 {
+							continue
+						}
+						if deltavertex == -1 {
+							deltavertex = v
+						} else if c := ar.Cmp(dualvar[v], dualvar[deltavertex]); c < 0 || (c == 0 && mate[v] == -1) {
+							deltavertex = v
 						}
 					}
 				}
+				delta = ar.Sub(e.vertexDual(deltavertex), e.floor)
+			} else if !e.maxCardinality {
+				deltatype = 1
+				delta = e.minDual()
+			}
 
+			if e.pq != nil {
+				if t, d, k, b := e.queuedDelta(); t != -1 && (deltatype == -1 || ar.Cmp(d, delta) < 0) {
+					deltatype, delta, deltaedge, deltablossom = t, d, k, b
+				}
+			} else {
 				// Delta type 2
 				for v := 0; v < nvertex; v++ {
 					if label[inblossom[v]] == 0 && bestedge[v] != -1 {
-						d := slack(bestedge[v])
-						if deltatype == -1 || d < delta {
+						d := e.slack(bestedge[v])
+						if deltatype == -1 || ar.Cmp(d, delta) < 0 {
 							delta = d
 							deltatype = 2
 							deltaedge = bestedge[v]
@@ -832,13 +1353,12 @@ func (mwm *MaximumWeightedMatching) maxWeightMatchingInternal(edges []GraphEdge,
 
 				// Delta type 3
 				for b := 0; b < nvertex*2; b++ {
-					if blossomparent[b] == -1 && label[b] == 1 && bestedge[b] != -1 {
-						kslack := slack(bestedge[b])
-						if !(kslack%2 == 0) {
-							panic("assertion failed")
+					if e.blossomparent[b] == -1 && label[b] == 1 && bestedge[b] != -1 {
+						d, exact := ar.Half(e.slack(bestedge[b]))
+						if !exact {
+							e.fail("delta3: odd slack")
 						}
-						d := kslack / 2
-						if deltatype == -1 || d < delta {
+						if deltatype == -1 || ar.Cmp(d, delta) < 0 {
 							delta = d
 							deltatype = 3
 							deltaedge = bestedge[b]
@@ -848,109 +1368,217 @@ func (mwm *MaximumWeightedMatching) maxWeightMatchingInternal(edges []GraphEdge,
 
 				// Delta type 4
 				for b := nvertex; b < nvertex*2; b++ {
-					if blossombase[b] >= 0 && blossomparent[b] == -1 && label[b] == 2 && (deltatype == -1 || dualvar[b] < delta) {
+					if e.blossombase[b] >= 0 && e.blossomparent[b] == -1 && label[b] == 2 && (deltatype == -1 || ar.Cmp(dualvar[b], delta) < 0) {
 						delta = dualvar[b]
 						deltatype = 4
 						deltablossom = b
 					}
 				}
+			}
 
-				if deltatype == -1 {
-					if !maxCardinality {
-						panic("assertion failed")
-					}
-					deltatype = 1
-					delta = 0
-					for v := 0; v < nvertex; v++ {
-						if dualvar[v] > delta {
-							delta = dualvar[v]
-						}
-					}
+			if deltatype == -1 {
+				if !e.maxCardinality {
+					e.fail("delta: no delta found")
 				}
+				deltatype = 1
+				delta = e.minDual()
+				if ar.Cmp(delta, ar.Zero()) < 0 {
+					delta = ar.Zero()
+				}
+			}
+
+			// A delta within the tolerance of zero is exactly zero
+			if ar.Cmp(delta, ar.Zero()) == 0 {
+				delta = ar.Zero()
+			}
 
-				// Update dual variables
+			// Update dual variables, which the priority queue engine does lazily
+			if e.pq != nil {
+				e.pq.delta = ar.Add(e.pq.delta, delta)
+			} else {
 				for v := 0; v < nvertex; v++ {
 					if label[inblossom[v]] == 1 {
-						dualvar[v] -= delta
+						dualvar[v] = ar.Sub(dualvar[v], delta)
 					} else if label[inblossom[v]] == 2 {
-						dualvar[v] += delta
+						dualvar[v] = ar.Add(dualvar[v], delta)
 					}
 				}
 
 				for b := nvertex; b < nvertex*2; b++ {
-					if blossombase[b] >= 0 && blossomparent[b] == -1 {
+					if e.blossombase[b] >= 0 && e.blossomparent[b] == -1 {
 						if label[b] == 1 {
-							dualvar[b] += delta
+							dualvar[b] = ar.Add(dualvar[b], delta)
 						} else if label[b] == 2 {
-							dualvar[b] -= delta
+							dualvar[b] = ar.Sub(dualvar[b], delta)
 						}
 					}
 				}
+			}
 
-				if mwm.DebugMode {
-					fmt.Printf("DEBUG: delta%d=%d.000000\n", deltatype, delta)
-				}
+			if tracer != nil {
+				tracer.OnDualUpdate(deltatype, delta)
+			}
+			if stats != nil {
+				stats.DeltaTypes[deltatype]++
+				e.lap(&stats.DualUpdateTime)
+			}
 
-				// Perform action based on delta type
-				if deltatype == 1 {
-					break
-				} else if deltatype == 2 {
-					allowedge[deltaedge] = true
-					edge := edges[deltaedge]
-					i := int(edge.Node1)
-					j := int(edge.Node2)
-					if label[inblossom[i]] == 0 {
-						i, j = j, i
-					}
-					if !(label[inblossom[i]] == 1) {
-						panic("assertion failed")
-					}
-					queue = append(queue, i)
-				} else if deltatype == 3 {
-					allowedge[deltaedge] = true
-					edge := edges[deltaedge]
-					i := int(edge.Node1)
-					if !(label[inblossom[i]] == 1) {
-						panic("assertion failed")
+			// Perform action based on delta type
+			if deltatype == 1 {
+				if e.warm && !e.maxCardinality {
+					// The vertex at the floor becomes free and the root of its tree is matched instead
+					if e.pq != nil {
+						e.pq.joined = [2]int{e.pq.tree[deltavertex], -1}
 					}
-					queue = append(queue, i)
-				} else if deltatype == 4 {
-					expandBlossom(deltablossom, false)
+					e.augmentPath(deltavertex, -1)
+					released = true
+				}
+				break
+			} else if deltatype == 2 {
+				allowedge[deltaedge] = true
+				edge := e.edges[deltaedge]
+				i := int(edge.Node1)
+				j := int(edge.Node2)
+				if label[inblossom[i]] == 0 {
+					i, j = j, i
+				}
+				if !(label[inblossom[i]] == 1) {
+					e.fail("delta2: edge without S endpoint")
+				}
+				e.queue = append(e.queue, i)
+			} else if deltatype == 3 {
+				allowedge[deltaedge] = true
+				edge := e.edges[deltaedge]
+				i := int(edge.Node1)
+				if !(label[inblossom[i]] == 1) {
+					e.fail("delta3: edge without S endpoint")
+				}
+				e.queue = append(e.queue, i)
+			} else if deltatype == 4 {
+				e.expandBlossom(deltablossom, false)
+				if stats != nil {
+					e.lap(&stats.ExpandTime)
 				}
 			}
+		}
 
-			if !augmented {
-				break
+		if !augmented && !released {
+			break
+		}
+		if augmented {
+			e.augmentations++
+			if e.opts.recordCurve {
+				e.curve = append(e.curve, e.matchedWeight())
 			}
+		}
+		if stats != nil {
+			e.lap(&stats.ScanTime)
+		}
 
-			// Expand blossoms with zero dual variable
+		// Expand blossoms with zero dual variable
+		if e.pq != nil {
+			e.releaseTrees()
+		} else {
 			for b := nvertex; b < nvertex*2; b++ {
-				if blossomparent[b] == -1 && blossombase[b] >= 0 && label[b] == 1 && dualvar[b] == 0 {
-					expandBlossom(b, true)
+				if e.blossomparent[b] == -1 && e.blossombase[b] >= 0 && label[b] == 1 && ar.Cmp(dualvar[b], ar.Zero()) == 0 {
+					e.expandBlossom(b, true)
 				}
 			}
 		}
+		if stats != nil {
+			e.lap(&stats.ExpandTime)
+		}
+	}
+	if e.pq != nil {
+		e.settleDuals()
+	}
+	if stats != nil {
+		e.lap(&stats.ScanTime)
+		stats.Augmentations = e.augmentations
+	}
 
-		// Restore matching
-		for v := 0; v < nvertex; v++ {
-			if mate[v] >= 0 {
-				mate[v] = endpoint[mate[v]]
-			}
+	// Restore matching
+	for v := 0; v < nvertex; v++ {
+		e.mateedge[v] = -1
+		if mate[v] >= 0 {
+			e.mateedge[v] = int(mate[v]) / 2
+			mate[v] = e.endpoint[mate[v]]
 		}
+	}
 
-		// Verify correctness
-		for v := 0; v < nvertex; v++ {
-			if mate[v] != -1 && mate[mate[v]] != int64(v) {
-				panic("mate check failed")
-			}
+	// Verify correctness
+	for v := 0; v < nvertex; v++ {
+		if mate[v] != -1 && mate[mate[v]] != int64(v) {
+			e.fail("verify: inconsistent mate")
 		}
+	}
+
+	if tracer != nil {
+		tracer.OnFinish(mate)
+	}
+
+	if stats != nil {
+		e.lap(&stats.FinishTime)
+		stats.TotalTime = e.mark.Sub(e.start)
+	}
+}
 
-		if mwm.DebugMode {
-			fmt.Printf("DEBUG: MATE = %v\n", mate)
+// matchedWeight returns the weight of the current matching, while mate still holds endpoints
+func (e *engine[W]) matchedWeight() W {
+	total := e.ar.Zero()
+	for v := 0; v < e.nvertex; v++ {
+		if p := e.mate[v]; p >= 0 && e.endpoint[p] > int64(v) {
+			total = e.ar.Add(total, e.edges[p/2].Weight)
 		}
+	}
+	return total
+}
 
-		return mate
+// solution copies the final state of a run, so that it does not share memory with the engine
+func (e *engine[W]) solution() *solution[W] {
+	nvertex := e.nvertex
+	sol := &solution[W]{
+		mate:        append(make([]int64, 0, nvertex), e.mate...),
+		mateedge:    append(make([]int, 0, nvertex), e.mateedge...),
+		interrupted: e.interrupted,
+		stats:       e.stats,
+	}
+	if e.opts.recordCurve {
+		sol.curve = append(make([]W, 0, len(e.curve)), e.curve...)
+	}
+	if e.interrupted != nil {
+		// The dual solution of an interrupted stage is no optimality certificate
+		return sol
+	}
+	sol.vertexDuals = append(make([]W, 0, nvertex), e.dualvar[:nvertex]...)
+
+	// Collect the laminar family of blossoms with their duals
+	blossomindex := make([]int, nvertex*2)
+	sol.blossoms = make([]WeightedBlossom[W], 0)
+	for b := nvertex; b < nvertex*2; b++ {
+		blossomindex[b] = -1
+		if e.blossombase[b] >= 0 {
+			blossomindex[b] = len(sol.blossoms)
+			e.leaves = e.appendLeaves(e.leaves[:0], b)
+			vertices := make([]int64, len(e.leaves))
+			for i, v := range e.leaves {
+				vertices[i] = int64(v)
+			}
+			sol.blossoms = append(sol.blossoms, WeightedBlossom[W]{Dual: e.dualvar[b], Vertices: vertices})
+		}
+	}
+	for b := nvertex; b < nvertex*2; b++ {
+		if blossomindex[b] >= 0 {
+			sol.blossoms[blossomindex[b]].Parent = -1
+			if e.blossomparent[b] >= 0 {
+				sol.blossoms[blossomindex[b]].Parent = blossomindex[e.blossomparent[b]]
+			}
+		}
 	}
 
-	return mainLoop()
+	if e.stats != nil {
+		e.lap(&e.stats.FinishTime)
+		e.stats.TotalTime = e.mark.Sub(e.start)
+	}
+	return sol
 }
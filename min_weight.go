@@ -0,0 +1,165 @@
+package mwm
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// CardinalityObjective selects the matchings among which MinWeightMatching minimizes the total weight
+type CardinalityObjective int
+
+const (
+	// AnyCardinality allows matchings of any size, so only edges with negative weight are worth matching
+	AnyCardinality CardinalityObjective = iota
+	// MaximumCardinality restricts the search to matchings with the maximum number of edges
+	MaximumCardinality
+	// ExactCardinality restricts the search to matchings with exactly k edges
+	ExactCardinality
+	// AtMostCardinality restricts the search to matchings with at most k edges
+	AtMostCardinality
+)
+
+// MinWeightMatching returns the matching of minimum total weight among the matchings selected
+// by objective. The parameter k is the number of edges for ExactCardinality and AtMostCardinality
+// and is ignored otherwise.
+// If the graph has no matching with k edges, the minimum weight matching of maximum cardinality
+// is returned together with ErrCardinalityUnreachable.
+//
+// The matching is computed as a maximum weight matching on the negated weights, so VertexDuals
+// and Blossoms certify optimality for the negated edges. For ExactCardinality the algorithm
+// runs in maximum cardinality mode and stops after k augmentations: the free vertices then
+// share the smallest vertex dual, which proves optimality among matchings with k edges.
+// AtMostCardinality stops after k augmentations without maximum cardinality mode
+func (mwm *MaximumWeightedMatching) MinWeightMatching(edges []GraphEdge, objective CardinalityObjective, k int) (*MatchingResult, error) {
+	if err := validateEdges(Int64Arithmetic{}, edges); err != nil {
+		return nil, err
+	}
+	opts, err := objectiveOptions(objective, k)
+	if err != nil {
+		return nil, err
+	}
+
+	return int64Fallback(edges,
+		func() (*MatchingResult, error) {
+			return minWeightMatching(mwm, Int64Arithmetic{}, edges, opts)
+		},
+		func(edges []WeightedEdge[*big.Int]) (*WeightedMatchingResult[*big.Int], error) {
+			return minWeightMatching(mwm, BigIntArithmetic{}, edges, opts)
+		})
+}
+
+// MinWeightMatchingWith is MinWeightMatching for weights of type W, computing with the operations of ar
+func MinWeightMatchingWith[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], objective CardinalityObjective, k int) (*WeightedMatchingResult[W], error) {
+	if err := validateEdges(ar, edges); err != nil {
+		return nil, err
+	}
+	opts, err := objectiveOptions(objective, k)
+	if err != nil {
+		return nil, err
+	}
+	return minWeightMatching(mwm, ar, edges, opts)
+}
+
+// MinWeightPerfectMatching returns the perfect matching of minimum total weight, which matches
+// every vertex appearing in edges. If the graph has no perfect matching, the minimum weight
+// matching among those of maximum cardinality is returned together with ErrNoPerfectMatching.
+//
+// The matching is computed as a maximum cardinality matching on the negated weights, so
+// VertexDuals and Blossoms certify optimality for the negated edges. Weights beyond
+// SafeWeightLimit are handled with big integer arithmetic like in MaxWeightMatchingE
+func (mwm *MaximumWeightedMatching) MinWeightPerfectMatching(edges []GraphEdge) (*MatchingResult, error) {
+	if err := validateEdges(Int64Arithmetic{}, edges); err != nil {
+		return nil, err
+	}
+
+	return int64Fallback(edges,
+		func() (*MatchingResult, error) {
+			return minWeightPerfectMatching(mwm, Int64Arithmetic{}, edges)
+		},
+		func(edges []WeightedEdge[*big.Int]) (*WeightedMatchingResult[*big.Int], error) {
+			return minWeightPerfectMatching(mwm, BigIntArithmetic{}, edges)
+		})
+}
+
+// MinWeightPerfectMatchingWith is MinWeightPerfectMatching for weights of type W, computing with the operations of ar
+func MinWeightPerfectMatchingWith[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W]) (*WeightedMatchingResult[W], error) {
+	if err := validateEdges(ar, edges); err != nil {
+		return nil, err
+	}
+	return minWeightPerfectMatching(mwm, ar, edges)
+}
+
+// objectiveOptions translates a cardinality objective into solver options
+func objectiveOptions(objective CardinalityObjective, k int) (solveOptions, error) {
+	switch objective {
+	case AnyCardinality:
+		return solveOptions{}, nil
+	case MaximumCardinality:
+		return solveOptions{maxCardinality: true}, nil
+	case ExactCardinality, AtMostCardinality:
+		if k < 0 {
+			return solveOptions{}, fmt.Errorf("%w: negative cardinality %d", ErrCardinalityUnreachable, k)
+		}
+		return solveOptions{maxCardinality: objective == ExactCardinality, limitAugmentations: true, augmentationLimit: k}, nil
+	}
+	return solveOptions{}, fmt.Errorf("mwm: unknown cardinality objective %d", objective)
+}
+
+// minWeightPerfectMatching solves the maximum cardinality problem on the negated weights of validated edges
+func minWeightPerfectMatching[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W]) (*WeightedMatchingResult[W], error) {
+	result, err := minWeightMatching(mwm, ar, edges, solveOptions{maxCardinality: true})
+	if err != nil {
+		return nil, err
+	}
+	return result, checkPerfect(edges, result.Mate)
+}
+
+// minWeightMatching solves the problem selected by opts on the negated weights of validated edges
+func minWeightMatching[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], opts solveOptions) (result *WeightedMatchingResult[W], err error) {
+	defer recoverFailure(&err)
+
+	matching, err := solveWith(mwm, ar, negateEdges(ar, edges), opts)
+	if err != nil {
+		return nil, err
+	}
+	restoreWeights(ar, edges, matching)
+	return matching, checkCardinality(opts, matching.Cardinality)
+}
+
+// checkCardinality reports ErrCardinalityUnreachable if an exact number of edges was requested but not reached
+func checkCardinality(opts solveOptions, cardinality int) error {
+	if opts.maxCardinality && opts.limitAugmentations && cardinality < opts.augmentationLimit {
+		return fmt.Errorf("%w: %d edges requested, at most %d possible", ErrCardinalityUnreachable, opts.augmentationLimit, cardinality)
+	}
+	return nil
+}
+
+// negateEdges returns a copy of edges with negated weights
+func negateEdges[W any](ar Arithmetic[W], edges []WeightedEdge[W]) []WeightedEdge[W] {
+	negated := make([]WeightedEdge[W], len(edges))
+	for k, edge := range edges {
+		negated[k] = WeightedEdge[W]{Node1: edge.Node1, Node2: edge.Node2, Weight: ar.Sub(ar.Zero(), edge.Weight)}
+	}
+	return negated
+}
+
+// restoreWeights replaces the weights of a result computed on transformed edges by the original weights
+func restoreWeights[W any](ar Arithmetic[W], edges []WeightedEdge[W], result *WeightedMatchingResult[W]) {
+	result.TotalWeight = ar.Zero()
+	for i, k := range result.EdgeIndices {
+		result.Weights[i] = edges[k].Weight
+		result.TotalWeight = ar.Add(result.TotalWeight, edges[k].Weight)
+	}
+}
+
+// checkPerfect reports ErrNoPerfectMatching if a vertex appearing in edges is unmatched
+func checkPerfect[W any](edges []WeightedEdge[W], mate []int64) error {
+	for _, edge := range edges {
+		for _, v := range []int64{edge.Node1, edge.Node2} {
+			if mate[v] == -1 {
+				return fmt.Errorf("%w: vertex %d is unmatched", ErrNoPerfectMatching, v)
+			}
+		}
+	}
+	return nil
+}
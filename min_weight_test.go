@@ -0,0 +1,207 @@
+package mwm
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestMinWeightPerfectMatching - test a small graph whose cheapest perfect matching avoids the cheapest edge
+func TestMinWeightPerfectMatching(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{
+		{Node1: 0, Node2: 1, Weight: 1},
+		{Node1: 1, Node2: 2, Weight: 5},
+		{Node1: 2, Node2: 3, Weight: 1},
+		{Node1: 0, Node2: 3, Weight: 6},
+		{Node1: 1, Node2: 3, Weight: 0},
+	}
+	result, err := matcher.MinWeightPerfectMatching(edges)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.TotalWeight != 2 || result.Cardinality != 2 {
+		t.Errorf("Expected 2 edges of weight 2, got %d edges of weight %d", result.Cardinality, result.TotalWeight)
+	}
+}
+
+// TestNoPerfectMatching - test that a graph without a perfect matching is reported
+func TestNoPerfectMatching(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{
+		{Node1: 0, Node2: 1, Weight: 3},
+		{Node1: 0, Node2: 2, Weight: 2},
+		{Node1: 0, Node2: 3, Weight: 1},
+	}
+	result, err := matcher.MinWeightPerfectMatching(edges)
+	if !errors.Is(err, ErrNoPerfectMatching) {
+		t.Fatalf("Expected ErrNoPerfectMatching, got %v", err)
+	}
+	if result == nil || result.Cardinality != 1 || result.TotalWeight != 1 {
+		t.Errorf("Expected the cheapest single edge, got %v", result)
+	}
+}
+
+// TestMinWeightPerfectMatchingRandom - test random graphs against exhaustive search
+func TestMinWeightPerfectMatchingRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(8))
+	matcher := NewMaximumWeightedMatching()
+	for i := 0; i < 300; i++ {
+		nvertex := 2 + rng.Intn(11)
+		edges := randomEdges(rng, nvertex, 0.3+0.6*rng.Float64(), -30, 60)
+		result, err := matcher.MinWeightPerfectMatching(edges)
+		if err != nil && !errors.Is(err, ErrNoPerfectMatching) {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+
+		covered := make(map[int64]bool)
+		for _, edge := range edges {
+			covered[edge.Node1] = true
+			covered[edge.Node2] = true
+		}
+		cardinality, negatedWeight := bruteForceMatching(nvertex, negateEdges(Int64Arithmetic{}, edges), true)
+		if perfect := 2*cardinality == len(covered); perfect != (err == nil) {
+			t.Fatalf("Iteration %d: perfect=%t, got error %v\nedges: %v", i, perfect, err, edges)
+		}
+		if result.Cardinality != cardinality || result.TotalWeight != -negatedWeight {
+			t.Fatalf("Iteration %d: expected %d edges of weight %d, got %d edges of weight %d\nedges: %v",
+				i, cardinality, -negatedWeight, result.Cardinality, result.TotalWeight, edges)
+		}
+		if err := VerifyOptimum(negateEdges(Int64Arithmetic{}, edges), result); err != nil {
+			t.Fatalf("Iteration %d: %v", i, err)
+		}
+	}
+}
+
+// TestMinWeightPerfectMatchingExtremeWeights - test that the most negative int64 weight can be negated
+func TestMinWeightPerfectMatchingExtremeWeights(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{
+		{Node1: 0, Node2: 1, Weight: math.MinInt64},
+		{Node1: 1, Node2: 2, Weight: 0},
+		{Node1: 2, Node2: 3, Weight: 0},
+	}
+	result, err := matcher.MinWeightPerfectMatching(edges)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.TotalWeight != math.MinInt64 || result.Cardinality != 2 {
+		t.Errorf("Expected edges 0 and 2, got %v", result)
+	}
+}
+
+// TestMinWeightPerfectMatchingFloat - test the generic entry point with floating-point weights
+func TestMinWeightPerfectMatchingFloat(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []FloatGraphEdge{
+		{Node1: 0, Node2: 1, Weight: 0.5},
+		{Node1: 0, Node2: 2, Weight: 0.25},
+		{Node1: 1, Node2: 3, Weight: 0.5},
+		{Node1: 2, Node2: 3, Weight: 2.5},
+	}
+	result, err := MinWeightPerfectMatchingWith(matcher, Float64Arithmetic{Epsilon: DefaultEpsilon}, edges)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if math.Abs(result.TotalWeight-0.75) > 1e-12 {
+		t.Errorf("Expected total weight 0.75, got %v", result.TotalWeight)
+	}
+}
+
+// bruteForceProfile - helper function computing, for every cardinality, the best weight of a matching
+// of that size by exhaustive search. Unreachable cardinalities are missing from the result
+func bruteForceProfile(nvertex int, edges []GraphEdge) []int64 {
+	memo := make(map[int][]int64)
+	var search func(used int) []int64
+	search = func(used int) []int64 {
+		if profile, ok := memo[used]; ok {
+			return profile
+		}
+		v := 0
+		for v < nvertex && used&(1<<v) != 0 {
+			v++
+		}
+		if v == nvertex {
+			return []int64{0}
+		}
+		profile := append([]int64(nil), search(used|1<<v)...)
+		for _, edge := range edges {
+			i, j := int(edge.Node1), int(edge.Node2)
+			if j == v {
+				i, j = j, i
+			}
+			if i != v || used&(1<<j) != 0 {
+				continue
+			}
+			for size, weight := range search(used | 1<<i | 1<<j) {
+				if size+1 == len(profile) {
+					profile = append(profile, weight+edge.Weight)
+				} else if weight+edge.Weight > profile[size+1] {
+					profile[size+1] = weight + edge.Weight
+				}
+			}
+		}
+		memo[used] = profile
+		return profile
+	}
+	return search(0)
+}
+
+// TestMinWeightMatchingObjectives - test all cardinality objectives against exhaustive search
+func TestMinWeightMatchingObjectives(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	matcher := NewMaximumWeightedMatching()
+	for i := 0; i < 200; i++ {
+		nvertex := 2 + rng.Intn(11)
+		edges := randomEdges(rng, nvertex, 0.2+0.7*rng.Float64(), -30, 60)
+		negated := negateEdges(Int64Arithmetic{}, edges)
+		profile := bruteForceProfile(nvertex, negated)
+
+		result, err := matcher.MinWeightMatching(edges, AnyCardinality, 0)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if _, weight := bruteForceMatching(nvertex, negated, false); result.TotalWeight != -weight {
+			t.Fatalf("Iteration %d: expected weight %d for any cardinality, got %d", i, -weight, result.TotalWeight)
+		}
+
+		result, err = matcher.MinWeightMatching(edges, MaximumCardinality, 0)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if maxSize := len(profile) - 1; result.Cardinality != maxSize || result.TotalWeight != -profile[maxSize] {
+			t.Fatalf("Iteration %d: expected %d edges of weight %d, got %d edges of weight %d",
+				i, maxSize, -profile[maxSize], result.Cardinality, result.TotalWeight)
+		}
+
+		for k := 0; k <= len(profile); k++ {
+			result, err := matcher.MinWeightMatching(edges, ExactCardinality, k)
+			if k == len(profile) {
+				if !errors.Is(err, ErrCardinalityUnreachable) {
+					t.Fatalf("Iteration %d: expected ErrCardinalityUnreachable for %d edges, got %v", i, k, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("Iteration %d: unexpected error for %d edges: %v", i, k, err)
+			}
+			if result.Cardinality != k || result.TotalWeight != -profile[k] {
+				t.Fatalf("Iteration %d: expected %d edges of weight %d, got %d edges of weight %d\nedges: %v",
+					i, k, -profile[k], result.Cardinality, result.TotalWeight, edges)
+			}
+			if err := VerifyOptimum(negated, result); err != nil {
+				t.Fatalf("Iteration %d, %d edges: %v", i, k, err)
+			}
+		}
+	}
+}
+
+// TestMinWeightMatchingInvalidCardinality - test that a negative cardinality is rejected
+func TestMinWeightMatchingInvalidCardinality(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{{Node1: 0, Node2: 1, Weight: 1}}
+	if _, err := matcher.MinWeightMatching(edges, ExactCardinality, -1); !errors.Is(err, ErrCardinalityUnreachable) {
+		t.Errorf("Expected ErrCardinalityUnreachable, got %v", err)
+	}
+}
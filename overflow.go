@@ -0,0 +1,131 @@
+package mwm
+
+import (
+	"math"
+	"math/big"
+)
+
+// SafeWeightLimit is the largest absolute int64 weight for which the solver computes in int64.
+// Dual variables are kept at twice their LP value and may grow to a few times the largest weight,
+// so for larger weights, or when a checked int64 operation overflows, the solver falls back to big integers
+const SafeWeightLimit = math.MaxInt64 / 8
+
+// solveInt64 runs the algorithm on validated int64 edges, falling back to big integer arithmetic on overflow
+func (mwm *MaximumWeightedMatching) solveInt64(edges []GraphEdge, opts solveOptions) (*MatchingResult, error) {
+	return int64Fallback(edges,
+		func() (*MatchingResult, error) {
+			return solveWith(mwm, Int64Arithmetic{}, edges, opts)
+		},
+		func(edges []WeightedEdge[*big.Int]) (*WeightedMatchingResult[*big.Int], error) {
+			return solveWith(mwm, BigIntArithmetic{}, edges, opts)
+		})
+}
+
+// int64Fallback runs solveInt64 unless a weight exceeds SafeWeightLimit, and repeats the
+// computation with solveBig on big integer edges if the int64 arithmetic overflows.
+// An error returned together with a big integer result is passed on with the converted result
+func int64Fallback(edges []GraphEdge, solveInt64 func() (*MatchingResult, error), solveBig func([]WeightedEdge[*big.Int]) (*WeightedMatchingResult[*big.Int], error)) (*MatchingResult, error) {
+	if withinSafeWeightLimit(edges) {
+		result, err := solveInt64()
+		if err != ErrWeightOverflow {
+			return result, err
+		}
+	}
+
+	bigResult, err := solveBig(bigEdges(edges))
+	if bigResult == nil {
+		return nil, err
+	}
+	result, convertErr := int64Result(edges, bigResult)
+	if err == nil {
+		err = convertErr
+	}
+	return result, err
+}
+
+// withinSafeWeightLimit reports whether all weights are at most SafeWeightLimit in absolute value
+func withinSafeWeightLimit(edges []GraphEdge) bool {
+	for _, edge := range edges {
+		if edge.Weight > SafeWeightLimit || edge.Weight < -SafeWeightLimit {
+			return false
+		}
+	}
+	return true
+}
+
+// bigEdges converts int64 edges to big integer edges
+func bigEdges(edges []GraphEdge) []WeightedEdge[*big.Int] {
+	converted := make([]WeightedEdge[*big.Int], len(edges))
+	for k, edge := range edges {
+		converted[k] = WeightedEdge[*big.Int]{Node1: edge.Node1, Node2: edge.Node2, Weight: big.NewInt(edge.Weight)}
+	}
+	return converted
+}
+
+// bigResult converts an int64 result to a big integer result
+func bigResult(result *MatchingResult) *WeightedMatchingResult[*big.Int] {
+	converted := &WeightedMatchingResult[*big.Int]{
+		Pairs:          result.Pairs,
+		EdgeIndices:    result.EdgeIndices,
+		Weights:        make([]*big.Int, len(result.Weights)),
+		TotalWeight:    big.NewInt(result.TotalWeight),
+		Cardinality:    result.Cardinality,
+		Mate:           result.Mate,
+		MaxCardinality: result.MaxCardinality,
+		VertexDuals:    make([]*big.Int, len(result.VertexDuals)),
+		Blossoms:       make([]WeightedBlossom[*big.Int], len(result.Blossoms)),
+		Stats:          result.Stats,
+	}
+	for i, w := range result.Weights {
+		converted.Weights[i] = big.NewInt(w)
+	}
+	for v, d := range result.VertexDuals {
+		converted.VertexDuals[v] = big.NewInt(d)
+	}
+	for b, blossom := range result.Blossoms {
+		converted.Blossoms[b] = WeightedBlossom[*big.Int]{Dual: big.NewInt(blossom.Dual), Parent: blossom.Parent, Vertices: blossom.Vertices}
+	}
+	return converted
+}
+
+// int64Result converts a big integer result back to int64.
+// VertexDuals and Blossoms are left nil if any dual variable does not fit in int64.
+// If the total weight does not fit, TotalWeight is zero and ErrWeightOverflow is returned with the result
+func int64Result(edges []GraphEdge, result *WeightedMatchingResult[*big.Int]) (*MatchingResult, error) {
+	converted := &MatchingResult{
+		Pairs:          result.Pairs,
+		EdgeIndices:    result.EdgeIndices,
+		Weights:        make([]int64, len(result.EdgeIndices)),
+		Cardinality:    result.Cardinality,
+		Mate:           result.Mate,
+		MaxCardinality: result.MaxCardinality,
+		Stats:          result.Stats,
+	}
+	for i, k := range result.EdgeIndices {
+		converted.Weights[i] = edges[k].Weight
+	}
+
+	dualsFit := true
+	for _, d := range result.VertexDuals {
+		dualsFit = dualsFit && d.IsInt64()
+	}
+	for _, blossom := range result.Blossoms {
+		dualsFit = dualsFit && blossom.Dual.IsInt64()
+	}
+	if dualsFit {
+		converted.VertexDuals = make([]int64, len(result.VertexDuals))
+		for v, d := range result.VertexDuals {
+			converted.VertexDuals[v] = d.Int64()
+		}
+		converted.Blossoms = make([]Blossom, len(result.Blossoms))
+		for b, blossom := range result.Blossoms {
+			converted.Blossoms[b] = Blossom{Dual: blossom.Dual.Int64(), Parent: blossom.Parent, Vertices: blossom.Vertices}
+		}
+	}
+
+	if !result.TotalWeight.IsInt64() {
+		return converted, ErrWeightOverflow
+	}
+	converted.TotalWeight = result.TotalWeight.Int64()
+	return converted, nil
+}
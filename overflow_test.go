@@ -0,0 +1,123 @@
+package mwm
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestHugeWeights - test that weights near the int64 limit give the optimum of the scaled problem
+func TestHugeWeights(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	matcher := NewMaximumWeightedMatching()
+	const scale = 1 << 56
+	for i := 0; i < 100; i++ {
+		nvertex := 2 + rng.Intn(10)
+		edges := randomEdges(rng, nvertex, 0.5, -20, 60)
+		huge := make([]GraphEdge, len(edges))
+		for k, edge := range edges {
+			huge[k] = GraphEdge{Node1: edge.Node1, Node2: edge.Node2, Weight: edge.Weight * scale}
+		}
+		maxCardinality := i%2 == 1
+		result, err := matcher.MaxWeightMatchingE(huge, maxCardinality)
+		if err != nil && (!errors.Is(err, ErrWeightOverflow) || result == nil) {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		var weight int64
+		for _, k := range result.EdgeIndices {
+			weight += edges[k].Weight
+		}
+		cardinality, expected := bruteForceMatching(nvertex, edges, maxCardinality)
+		if weight != expected || (maxCardinality && result.Cardinality != cardinality) {
+			t.Fatalf("Iteration %d: expected weight %d, got %d\nedges: %v", i, expected, weight, edges)
+		}
+		if err == nil {
+			if result.TotalWeight != weight*scale {
+				t.Errorf("Iteration %d: expected total weight %d, got %d", i, weight*scale, result.TotalWeight)
+			}
+		}
+		if result.VertexDuals != nil {
+			if err := VerifyOptimum(huge, result); err != nil {
+				t.Fatalf("Iteration %d: %v", i, err)
+			}
+		}
+	}
+}
+
+// TestTotalWeightOverflow - test that a total weight beyond int64 is reported together with the matching
+func TestTotalWeightOverflow(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{
+		{Node1: 0, Node2: 1, Weight: math.MaxInt64},
+		{Node1: 2, Node2: 3, Weight: math.MaxInt64},
+		{Node1: 1, Node2: 2, Weight: 1},
+	}
+	result, err := matcher.MaxWeightMatchingE(edges, false)
+	if !errors.Is(err, ErrWeightOverflow) {
+		t.Fatalf("Expected ErrWeightOverflow, got %v", err)
+	}
+	if result == nil || len(result.Pairs) != 2 || result.EdgeIndices[0] != 0 || result.EdgeIndices[1] != 1 {
+		t.Errorf("Expected edges 0 and 1 to be matched, got %v", result)
+	}
+}
+
+// TestInt64ArithmeticOverflow - test that the checked int64 arithmetic reports an overflow instead of a wrong matching
+func TestInt64ArithmeticOverflow(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{
+		{Node1: 0, Node2: 1, Weight: math.MaxInt64 - 1},
+		{Node1: 1, Node2: 2, Weight: math.MaxInt64},
+	}
+	if _, err := MaxWeightMatchingWith(matcher, Int64Arithmetic{}, edges, false); !errors.Is(err, ErrWeightOverflow) {
+		t.Errorf("Expected ErrWeightOverflow, got %v", err)
+	}
+	result, err := matcher.MaxWeightMatchingE(edges, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.TotalWeight != math.MaxInt64 || result.EdgeIndices[0] != 1 {
+		t.Errorf("Expected edge 1 to be matched, got %v", result)
+	}
+}
+
+// TestInt64SlacksOverflow - test that the int64 slacks of the scan engine detect every overflow of the checked arithmetic
+func TestInt64SlacksOverflow(t *testing.T) {
+	var ar Int64Arithmetic
+	values := []int64{0, 1, -1, 1000, -1000, math.MaxInt64 / 4, math.MinInt64 / 4, math.MaxInt64 / 2, math.MinInt64 / 2,
+		math.MaxInt64/2 + 1, math.MaxInt64 - 1, math.MaxInt64, math.MinInt64 + 1, math.MinInt64}
+	for _, a := range values {
+		for _, b := range values {
+			for _, w := range values {
+				s := int64Slacks{dualvar: []int64{a, b}, edges: []GraphEdge{{Node1: 0, Node2: 1, Weight: w}}}
+				expected, expectedErr := recoverSlack(func() int64 { return ar.Sub(ar.Add(a, b), ar.Double(w)) })
+				slack, err := recoverSlack(func() int64 { return s.slack(0) })
+				if err != expectedErr || slack != expected {
+					t.Fatalf("Duals %d and %d, weight %d: expected slack %d and %v, got %d and %v", a, b, w, expected, expectedErr, slack, err)
+				}
+			}
+		}
+	}
+}
+
+// recoverSlack - helper function returning the slack computed by slack, or the error it panics with
+func recoverSlack(slack func() int64) (result int64, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = r.(error)
+		}
+	}()
+	return slack(), nil
+}
+
+// TestInt32ArithmeticOverflow - test that int32 overflow is detected
+func TestInt32ArithmeticOverflow(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []WeightedEdge[int32]{
+		{Node1: 0, Node2: 1, Weight: math.MaxInt32},
+		{Node1: 1, Node2: 2, Weight: math.MaxInt32 - 5},
+	}
+	if _, err := MaxWeightMatchingWith(matcher, Int32Arithmetic{}, edges, false); !errors.Is(err, ErrWeightOverflow) {
+		t.Errorf("Expected ErrWeightOverflow, got %v", err)
+	}
+}
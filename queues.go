@@ -0,0 +1,519 @@
+package mwm
+
+// Engine selects how the blossom algorithm finds the dual change of every substage
+type Engine int
+
+const (
+	// ScanEngine scans all vertices and blossoms for the dual change and updates every dual
+	// variable with it, which costs O(n) per substage and O(n³) in total
+	ScanEngine Engine = iota
+	// PriorityQueueEngine keeps the candidates of every delta type in priority queues and
+	// applies the dual change lazily, so a substage costs O(log m) plus the edges it scans.
+	// It tracks best edges per vertex and rescans the edges of a vertex whose best edge went
+	// stale, instead of merging heaps per blossom, so it is a practical speedup without the
+	// O(n·m·log n) bound of Galil, Micali and Gabow
+	PriorityQueueEngine
+)
+
+// dualQueues holds the state of the priority queue engine. The dual variables are stored relative
+// to delta, the total dual change of the run so far: a vertex in mode 1 (an S-blossom) has the
+// dual dualvar[v] - delta, a vertex in mode 2 (a T-blossom) has the dual dualvar[v] + delta, and a
+// top-level blossom the other way round. Mode 0 duals are stored as is.
+//
+// An augmentation only disturbs the two alternating trees it joins, so the engine keeps the other
+// trees with their labels from stage to stage. tree holds the root of the tree of every labeled
+// vertex, or -1, and members the vertices labeled in the tree of every root, some of which may
+// have left it since
+type dualQueues[W any] struct {
+	delta W
+	vmode []int8
+	bmode []int8
+	// fresh reports whether the queues hold no trees, so that the next stage labels its roots
+	fresh bool
+	// root is the free vertex with the smallest index, which has the smallest dual, or -1
+	root    int
+	tree    []int
+	members [][]int
+	// joined holds the roots of the trees joined by the last augmentation, or -1
+	joined [2]int
+	// pending holds the released vertices whose edges to S-vertices are not scanned yet
+	pending  []int
+	released []int
+
+	// delta2 holds non-S vertices keyed by the slack of their best edge plus delta, delta3 the
+	// endpoints of S-vertices with their least-slack edge to another S-blossom keyed by its slack
+	// plus twice delta, and delta4 T-blossoms keyed by their stored dual. Stale entries are dropped
+	// when they surface, and a stale edge of delta3 is replaced by the next least-slack edge
+	delta2 distanceHeap[W]
+	delta3 distanceHeap[W]
+	delta4 distanceHeap[W]
+	// delta1 holds the S-vertices keyed by their stored dual while a warm start lowers its roots
+	// to the floor
+	delta1 distanceHeap[W]
+}
+
+// initQueues prepares the queues for a graph with nvertex vertices
+func (q *dualQueues[W]) initQueues(ar Arithmetic[W], nvertex int) {
+	q.delta = ar.Zero()
+	q.vmode = resize(q.vmode, nvertex)
+	q.bmode = resize(q.bmode, nvertex*2)
+	for v := range q.vmode {
+		q.vmode[v] = 0
+	}
+	for b := range q.bmode {
+		q.bmode[b] = 0
+	}
+	q.fresh = true
+	q.root = -1
+	q.tree = resize(q.tree, nvertex)
+	q.members = resize(q.members, nvertex)
+	for v := range q.tree {
+		q.tree[v] = -1
+		q.members[v] = q.members[v][:0]
+	}
+	q.pending = q.pending[:0]
+	q.delta2.ar = ar
+	q.delta3.ar = ar
+	q.delta4.ar = ar
+	q.delta1.ar = ar
+	q.delta2.items = q.delta2.items[:0]
+	q.delta3.items = q.delta3.items[:0]
+	q.delta4.items = q.delta4.items[:0]
+	q.delta1.items = q.delta1.items[:0]
+}
+
+// dualMode returns the mode of a vertex or top-level blossom with label t
+func dualMode(t int) int8 {
+	switch t {
+	case 1, 5:
+		return 1
+	case 2:
+		return 2
+	}
+	return 0
+}
+
+// vertexDual returns the dual variable of vertex v
+func (e *engine[W]) vertexDual(v int) W {
+	if e.pq != nil {
+		switch e.pq.vmode[v] {
+		case 1:
+			return e.ar.Sub(e.dualvar[v], e.pq.delta)
+		case 2:
+			return e.ar.Add(e.dualvar[v], e.pq.delta)
+		}
+	}
+	return e.dualvar[v]
+}
+
+// queuedSlack returns the slack of edge k, twice its LP value, from the lazily updated duals
+func (e *engine[W]) queuedSlack(k int) W {
+	edge := &e.edges[k]
+	return e.ar.Sub(e.ar.Add(e.vertexDual(int(edge.Node1)), e.vertexDual(int(edge.Node2))), e.ar.Double(edge.Weight))
+}
+
+// blossomDual returns the dual variable of the non-trivial blossom b
+func (e *engine[W]) blossomDual(b int) W {
+	if e.pq != nil {
+		switch e.pq.bmode[b] {
+		case 1:
+			return e.ar.Add(e.dualvar[b], e.pq.delta)
+		case 2:
+			return e.ar.Sub(e.dualvar[b], e.pq.delta)
+		}
+	}
+	return e.dualvar[b]
+}
+
+// setVertexMode stores the dual of vertex v for mode m
+func (e *engine[W]) setVertexMode(v int, m int8) {
+	q := e.pq
+	if q.vmode[v] == m {
+		return
+	}
+	dual := e.vertexDual(v)
+	q.vmode[v] = m
+	switch m {
+	case 1:
+		dual = e.ar.Add(dual, q.delta)
+		if e.warm && !e.maxCardinality {
+			q.delta1.push(dual, int64(v))
+		}
+	case 2:
+		dual = e.ar.Sub(dual, q.delta)
+	}
+	e.dualvar[v] = dual
+}
+
+// setBlossomMode stores the dual of the non-trivial blossom b for mode m
+func (e *engine[W]) setBlossomMode(b int, m int8) {
+	q := e.pq
+	if q.bmode[b] == m {
+		return
+	}
+	dual := e.blossomDual(b)
+	q.bmode[b] = m
+	switch m {
+	case 1:
+		dual = e.ar.Sub(dual, q.delta)
+	case 2:
+		dual = e.ar.Add(dual, q.delta)
+	}
+	e.dualvar[b] = dual
+}
+
+// setLeafModes stores the duals of the vertices contained in blossom b for mode m
+func (e *engine[W]) setLeafModes(b int, m int8) {
+	if b < e.nvertex {
+		e.setVertexMode(b, m)
+		return
+	}
+	for _, t := range e.blossomchilds[b] {
+		e.setLeafModes(t, m)
+	}
+}
+
+// relabelDuals updates the modes of the top-level blossom b and its vertices after b got label t
+// in the tree of root, and queues the candidates for the dual change that the new label brings
+func (e *engine[W]) relabelDuals(b, t, root int) {
+	m := dualMode(t)
+	e.relabelLeaves(b, m, root)
+	if b >= e.nvertex {
+		e.setBlossomMode(b, m)
+		if t == 2 {
+			e.pq.delta4.push(e.dualvar[b], int64(b))
+		}
+	}
+}
+
+// relabelLeaves moves the vertices contained in blossom b to mode m and the tree of root,
+// queueing the best edges of vertices that become unlabeled
+func (e *engine[W]) relabelLeaves(b int, m int8, root int) {
+	if b >= e.nvertex {
+		for _, t := range e.blossomchilds[b] {
+			e.relabelLeaves(t, m, root)
+		}
+		return
+	}
+	q := e.pq
+	e.setVertexMode(b, m)
+	if q.tree[b] != root {
+		q.tree[b] = root
+		if root != -1 {
+			q.members[root] = append(q.members[root], b)
+		}
+	}
+	if m == 0 {
+		e.queueVertex(b)
+	}
+}
+
+// liveEdge reports whether edge k connects vertex v to an S-vertex of another blossom
+func (e *engine[W]) liveEdge(k, v int) bool {
+	edge := &e.edges[k]
+	s := int(edge.Node1 + edge.Node2 - int64(v))
+	return e.label[e.inblossom[s]] == 1 && e.inblossom[s] != e.inblossom[v]
+}
+
+// queueVertex queues the best edge of vertex v, which lies in an unlabeled blossom
+func (e *engine[W]) queueVertex(v int) {
+	k := e.bestedge[v]
+	if k == -1 {
+		return
+	}
+	if !e.liveEdge(k, v) {
+		e.recomputeBestEdge(v)
+		return
+	}
+	e.pq.delta2.push(e.ar.Add(e.queuedSlack(k), e.pq.delta), int64(v))
+}
+
+// recomputeBestEdge finds the least-slack edge from the non-S vertex v to an S-vertex after its
+// best edge lost the S-vertex, and queues it if v lies in an unlabeled blossom
+func (e *engine[W]) recomputeBestEdge(v int) {
+	best := -1
+	var bestslack W
+	for _, p := range e.neighbors(v) {
+		s := int(e.endpoint[p])
+		if e.label[e.inblossom[s]] != 1 || e.inblossom[s] == e.inblossom[v] {
+			continue
+		}
+		if kslack := e.queuedSlack(p / 2); best == -1 || e.ar.Cmp(kslack, bestslack) < 0 {
+			best, bestslack = p/2, kslack
+		}
+	}
+	e.bestedge[v] = best
+	if best != -1 && e.label[e.inblossom[v]] == 0 {
+		e.pq.delta2.push(e.ar.Add(bestslack, e.pq.delta), int64(v))
+	}
+}
+
+// offerBestEdge records edge k with slack kslack from an S-vertex to the non-S vertex w,
+// if it is the best edge of w
+func (e *engine[W]) offerBestEdge(w, k int, kslack W) {
+	best := e.bestedge[w]
+	if best != -1 && !e.liveEdge(best, w) {
+		e.recomputeBestEdge(w)
+		return
+	}
+	if best == -1 || e.ar.Cmp(kslack, e.queuedSlack(best)) < 0 {
+		e.bestedge[w] = k
+		if e.label[e.inblossom[w]] == 0 {
+			e.pq.delta2.push(e.ar.Add(kslack, e.pq.delta), int64(w))
+		}
+	}
+}
+
+// queueEdge queues the edge of endpoint p from an S-vertex to another S-blossom with slack kslack
+func (e *engine[W]) queueEdge(p int, kslack W) {
+	e.pq.delta3.push(e.ar.Add(kslack, e.ar.Double(e.pq.delta)), int64(p))
+}
+
+// requeueEdges queues the least-slack edge from the S-vertex v to another S-blossom, if any
+func (e *engine[W]) requeueEdges(v int) {
+	if e.label[e.inblossom[v]] != 1 {
+		return
+	}
+	best := -1
+	var bestslack W
+	for _, p := range e.neighbors(v) {
+		k := p / 2
+		w := int(e.endpoint[p])
+		if e.allowedge[k] || e.inblossom[v] == e.inblossom[w] || e.label[e.inblossom[w]] != 1 {
+			continue
+		}
+		if kslack := e.queuedSlack(k); best == -1 || e.ar.Cmp(kslack, bestslack) < 0 {
+			best, bestslack = p^1, kslack
+		}
+	}
+	if best != -1 {
+		e.queueEdge(best, bestslack)
+	}
+}
+
+// releaseTrees removes the labels of the trees joined by the last augmentation, or of the tree
+// of a vertex released by a warm start, whose vertices wait in pending for the scan of their
+// edges to the remaining S-vertices. Like the end of a stage of the scan engine, it expands
+// their S-blossoms with zero dual
+func (e *engine[W]) releaseTrees() {
+	q := e.pq
+	released := q.released[:0]
+	for _, r := range q.joined {
+		// After a warm start an augmenting path may end in a free vertex outside of all trees
+		if r == -1 {
+			continue
+		}
+		for _, v := range q.members[r] {
+			if q.tree[v] == r {
+				q.tree[v] = -1
+				released = append(released, v)
+			}
+		}
+		q.members[r] = q.members[r][:0]
+	}
+
+	for _, v := range released {
+		b := e.inblossom[v]
+		if e.label[b] == 0 {
+			continue
+		}
+		wasS := e.label[b] == 1
+		e.label[b] = 0
+		e.setLeafModes(b, 0)
+		if b >= e.nvertex {
+			e.setBlossomMode(b, 0)
+			if wasS && e.ar.Cmp(e.dualvar[b], e.ar.Zero()) == 0 {
+				e.expandBlossom(b, true)
+			}
+		}
+	}
+
+	// Clear the labels of the vertices and all blossoms containing them
+	for _, v := range released {
+		for b := v; b != -1 && e.label[b] != -1; b = e.blossomparent[b] {
+			e.label[b] = 0
+			e.labelend[b] = -1
+			e.bestedge[b] = -1
+		}
+	}
+
+	// Edges of released vertices may lose their tightness, and vertices of the remaining
+	// T-blossoms that were reached from a released S-vertex are reached from another S-vertex
+	// over an allowed edge, if any
+	for _, v := range released {
+		for _, p := range e.neighbors(v) {
+			e.allowedge[p/2] = false
+			w := int(e.endpoint[p])
+			if bw := e.inblossom[w]; bw != w && e.label[bw] == 2 && e.label[w] == 2 && e.labelend[w] == p^1 {
+				e.remarkVertex(w)
+			}
+		}
+		q.pending = append(q.pending, v)
+	}
+	q.released = released
+
+	// Drop the released S-vertices from the scan queue
+	queue := e.queue[:0]
+	for _, v := range e.queue {
+		if e.label[e.inblossom[v]] == 1 {
+			queue = append(queue, v)
+		}
+	}
+	e.queue = queue
+
+	for q.root != -1 && e.mate[q.root] != -1 {
+		q.root++
+		if q.root == e.nvertex {
+			q.root = -1
+		}
+	}
+}
+
+// remarkVertex marks the vertex w of a T-blossom as reached over another allowed edge from an
+// S-vertex, or clears its mark
+func (e *engine[W]) remarkVertex(w int) {
+	e.label[w] = 0
+	e.labelend[w] = -1
+	for _, p := range e.neighbors(w) {
+		if s := int(e.endpoint[p]); e.allowedge[p/2] && e.label[e.inblossom[s]] == 1 {
+			e.label[w] = 2
+			e.labelend[w] = p
+			break
+		}
+	}
+	e.recomputeBestEdge(w)
+}
+
+// scanReleased scans the edges from S-vertices to the vertices released by the last augmentation,
+// and reports whether this augmented the matching
+func (e *engine[W]) scanReleased() bool {
+	q := e.pq
+	for len(q.pending) > 0 {
+		u := q.pending[len(q.pending)-1]
+		for _, p := range e.neighbors(u) {
+			s := int(e.endpoint[p])
+			if e.label[e.inblossom[s]] != 1 {
+				continue
+			}
+			augmented, between, kslack := e.scanEdge(s, p^1)
+			if augmented {
+				return true
+			}
+			if between {
+				e.queueEdge(p, kslack)
+			}
+		}
+		q.pending = q.pending[:len(q.pending)-1]
+	}
+	return false
+}
+
+// settleDuals stores all duals as is, which ends the lazy dual updates
+func (e *engine[W]) settleDuals() {
+	q := e.pq
+	for v := 0; v < e.nvertex; v++ {
+		e.setVertexMode(v, 0)
+	}
+	for b := e.nvertex; b < e.nvertex*2; b++ {
+		e.setBlossomMode(b, 0)
+	}
+	q.delta = e.ar.Zero()
+}
+
+// minDual returns the smallest vertex dual. Without a warm start every free vertex has it,
+// because the free vertices are S-vertices in every substage, and so do the free vertices of a
+// warm start once they have all reached the floor
+func (e *engine[W]) minDual() W {
+	if e.pq != nil && e.pq.root != -1 {
+		return e.vertexDual(e.pq.root)
+	}
+	delta := e.vertexDual(0)
+	for v := 1; v < e.nvertex; v++ {
+		if d := e.vertexDual(v); e.ar.Cmp(d, delta) < 0 {
+			delta = d
+		}
+	}
+	return delta
+}
+
+// floorVertex returns the S-vertex with the smallest dual, which a warm start lowers to the floor
+// next, dropping the stale entries of the queue on the way. Unlike the scan engine it does not
+// prefer a root among vertices with the same dual
+func (e *engine[W]) floorVertex() int {
+	q := e.pq
+	for len(q.delta1.items) > 0 {
+		top := q.delta1.items[0]
+		v := int(top.item)
+		if q.vmode[v] == 1 && e.label[e.inblossom[v]] == 1 && e.ar.Cmp(e.dualvar[v], top.dist) == 0 {
+			return v
+		}
+		q.delta1.pop()
+	}
+	e.fail("delta1: no S-vertex")
+	return -1
+}
+
+// queuedDelta finds the dual change of delta types 2, 3 and 4 at the top of the queues,
+// dropping the stale entries on the way. It returns deltatype -1 if the queues hold no candidate
+func (e *engine[W]) queuedDelta() (deltatype int, delta W, deltaedge, deltablossom int) {
+	ar := e.ar
+	q := e.pq
+	deltatype = -1
+
+	// Delta type 2: the least slack of an edge from an S-vertex to a vertex in an unlabeled blossom
+	for len(q.delta2.items) > 0 {
+		top := q.delta2.items[0]
+		v := int(top.item)
+		k := e.bestedge[v]
+		if k == -1 || e.label[e.inblossom[v]] != 0 {
+			q.delta2.pop()
+			continue
+		}
+		// The best edge may have lost its S-vertex, or its slack grew while the S-vertex was
+		// released, so that another edge may be better now
+		d := e.queuedSlack(k)
+		if !e.liveEdge(k, v) || ar.Cmp(ar.Add(d, q.delta), top.dist) != 0 {
+			q.delta2.pop()
+			e.recomputeBestEdge(v)
+			continue
+		}
+		deltatype, delta, deltaedge = 2, d, k
+		break
+	}
+
+	// Delta type 3: half the least slack of an edge between two S-blossoms
+	for len(q.delta3.items) > 0 {
+		top := q.delta3.items[0]
+		p := int(top.item)
+		k := p / 2
+		edge := &e.edges[k]
+		b1, b2 := e.inblossom[edge.Node1], e.inblossom[edge.Node2]
+		if !e.allowedge[k] && b1 != b2 && e.label[b1] == 1 && e.label[b2] == 1 &&
+			ar.Cmp(ar.Add(e.queuedSlack(k), ar.Double(q.delta)), top.dist) == 0 {
+			d, exact := ar.Half(e.queuedSlack(k))
+			if !exact {
+				e.fail("delta3: odd slack")
+			}
+			if deltatype == -1 || ar.Cmp(d, delta) < 0 {
+				deltatype, delta, deltaedge = 3, d, k
+			}
+			break
+		}
+		q.delta3.pop()
+		e.requeueEdges(int(e.endpoint[p]))
+	}
+
+	// Delta type 4: the least dual of a T-blossom
+	for len(q.delta4.items) > 0 {
+		top := q.delta4.items[0]
+		b := int(top.item)
+		if e.blossombase[b] >= 0 && e.blossomparent[b] == -1 && e.label[b] == 2 && ar.Cmp(e.dualvar[b], top.dist) == 0 {
+			if d := e.blossomDual(b); deltatype == -1 || ar.Cmp(d, delta) < 0 {
+				deltatype, delta, deltablossom = 4, d, b
+			}
+			break
+		}
+		q.delta4.pop()
+	}
+	return deltatype, delta, deltaedge, deltablossom
+}
@@ -0,0 +1,144 @@
+package mwm
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestPriorityQueueEngineRandom - test the priority queue engine against the scan engine and the certificate check
+func TestPriorityQueueEngineRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(32))
+	scan := NewMaximumWeightedMatching()
+	queued := NewMaximumWeightedMatching()
+	queued.Engine = PriorityQueueEngine
+	for i := 0; i < 1000; i++ {
+		maxCardinality := i%2 == 1
+		// Few distinct weights produce many ties, blossoms and blossom expansions
+		maxWeight := int64(3 + rng.Intn(100))
+		edges := randomEdges(rng, 2+rng.Intn(40), 0.05+0.5*rng.Float64(), -maxWeight/3, maxWeight)
+		expected, err := scan.MaxWeightMatchingE(edges, maxCardinality)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		result, err := queued.MaxWeightMatchingE(edges, maxCardinality)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if result.TotalWeight != expected.TotalWeight || result.Cardinality != expected.Cardinality {
+			t.Fatalf("Iteration %d: expected %d edges of weight %d, got %d edges of weight %d\nedges: %v",
+				i, expected.Cardinality, expected.TotalWeight, result.Cardinality, result.TotalWeight, edges)
+		}
+		if err := VerifyOptimum(edges, result); err != nil {
+			t.Fatalf("Iteration %d: invalid certificate: %v\nedges: %v", i, err, edges)
+		}
+	}
+}
+
+// TestPriorityQueueEngineBruteForce - test the priority queue engine on small graphs against exhaustive search
+func TestPriorityQueueEngineBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(33))
+	matcher := NewMaximumWeightedMatching()
+	matcher.Engine = PriorityQueueEngine
+	for i := 0; i < 500; i++ {
+		nvertex := 2 + rng.Intn(9)
+		maxCardinality := i%2 == 1
+		edges := randomEdges(rng, nvertex, 0.6, -5, 10)
+		cardinality, weight := bruteForceMatching(nvertex, edges, maxCardinality)
+		result, err := matcher.MaxWeightMatchingE(edges, maxCardinality)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if result.TotalWeight != weight || (maxCardinality && result.Cardinality != cardinality) {
+			t.Fatalf("Iteration %d: expected %d edges of weight %d, got %d edges of weight %d\nedges: %v",
+				i, cardinality, weight, result.Cardinality, result.TotalWeight, edges)
+		}
+	}
+}
+
+// TestPriorityQueueEngineFloat - test the priority queue engine with floating-point weights
+func TestPriorityQueueEngineFloat(t *testing.T) {
+	rng := rand.New(rand.NewSource(34))
+	scan := NewMaximumWeightedMatching()
+	queued := NewMaximumWeightedMatching()
+	queued.Engine = PriorityQueueEngine
+	ar := Float64Arithmetic{Epsilon: DefaultEpsilon}
+	for i := 0; i < 200; i++ {
+		edges := make([]FloatGraphEdge, 0)
+		for _, edge := range randomEdges(rng, 2+rng.Intn(30), 0.3, 0, 1000) {
+			edges = append(edges, FloatGraphEdge{Node1: edge.Node1, Node2: edge.Node2, Weight: float64(edge.Weight) / 8})
+		}
+		expected, err := MaxWeightMatchingWith(scan, ar, edges, i%2 == 1)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		result, err := MaxWeightMatchingWith(queued, ar, edges, i%2 == 1)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if ar.Cmp(result.TotalWeight, expected.TotalWeight) != 0 {
+			t.Fatalf("Iteration %d: expected weight %v, got %v", i, expected.TotalWeight, result.TotalWeight)
+		}
+		if err := VerifyOptimumWith(ar, edges, result); err != nil {
+			t.Fatalf("Iteration %d: invalid certificate: %v", i, err)
+		}
+	}
+}
+
+// TestPriorityQueueEngineWarmStart - test warm starts with the priority queue engine against the scan engine
+func TestPriorityQueueEngineWarmStart(t *testing.T) {
+	rng := rand.New(rand.NewSource(44))
+	scan := NewMaximumWeightedMatching()
+	queued := NewMaximumWeightedMatching()
+	queued.Engine = PriorityQueueEngine
+	for i := 0; i < 500; i++ {
+		maxCardinality := i%2 == 1
+		edges := randomEdges(rng, 2+rng.Intn(40), 0.05+0.5*rng.Float64(), -20, 50)
+		previous, err := scan.MaxWeightMatchingE(edges, maxCardinality)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		changed := perturbEdges(rng, edges, 1+rng.Intn(5), -20, 50)
+		start := &WarmStart[int64]{Mate: previous.Mate}
+		if i%4 < 2 {
+			start.VertexDuals = previous.VertexDuals
+		}
+		expected, err := scan.MaxWeightMatchingWarm(changed, maxCardinality, start)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		result, err := queued.MaxWeightMatchingWarm(changed, maxCardinality, start)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if result.TotalWeight != expected.TotalWeight || result.Cardinality != expected.Cardinality {
+			t.Fatalf("Iteration %d: expected %d edges of weight %d, got %d edges of weight %d\nedges: %v\nstart: %v",
+				i, expected.Cardinality, expected.TotalWeight, result.Cardinality, result.TotalWeight, changed, start)
+		}
+		if err := VerifyOptimum(changed, result); err != nil {
+			t.Fatalf("Iteration %d: invalid certificate: %v\nedges: %v", i, err, changed)
+		}
+	}
+}
+
+// BenchmarkPriorityQueueEngine - benchmark the priority queue engine on a large sparse graph
+func BenchmarkPriorityQueueEngine(b *testing.B) {
+	benchmarkSparse(b, PriorityQueueEngine)
+}
+
+// BenchmarkScanEngine - benchmark the scan engine on the graph of BenchmarkPriorityQueueEngine
+func BenchmarkScanEngine(b *testing.B) {
+	benchmarkSparse(b, ScanEngine)
+}
+
+// benchmarkSparse - helper function solving a random graph with 3000 vertices and about 6 edges per vertex
+func benchmarkSparse(b *testing.B, engine Engine) {
+	rng := rand.New(rand.NewSource(35))
+	edges := randomEdges(rng, 3000, 0.002, 1, 1000000)
+	matcher := NewMaximumWeightedMatching()
+	matcher.Engine = engine
+	for b.Loop() {
+		if _, err := matcher.MaxWeightMatchingE(edges, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package mwm
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// MaxWeightMatchingCovering returns the maximum weighted matching among the matchings that cover
+// every vertex in required. If no matching covers them all, the returned matching covers as many
+// required vertices as possible, has maximum weight among those, and comes with a *CoverageError
+// listing the required vertices it leaves uncovered. Other sets of the same size may be coverable
+// instead; a required vertex without edges is always uncovered.
+//
+// Every edge gains a bonus of twice the sum of all absolute weights per required endpoint, so
+// covering one more required vertex beats any difference in weight. The reported weights are the
+// true weights, and VertexDuals and Blossoms are nil since they would refer to the boosted edges
+func (mwm *MaximumWeightedMatching) MaxWeightMatchingCovering(edges []GraphEdge, required []int64) (*MatchingResult, error) {
+	if err := validateEdges(Int64Arithmetic{}, edges); err != nil {
+		return nil, err
+	}
+	if err := validateRequired(required); err != nil {
+		return nil, err
+	}
+
+	return int64Fallback(edges,
+		func() (*MatchingResult, error) {
+			return solveCovering(mwm, Int64Arithmetic{}, edges, required)
+		},
+		func(edges []WeightedEdge[*big.Int]) (*WeightedMatchingResult[*big.Int], error) {
+			return solveCovering(mwm, BigIntArithmetic{}, edges, required)
+		})
+}
+
+// MaxWeightMatchingCoveringWith is MaxWeightMatchingCovering for weights of type W, computing with the operations of ar
+func MaxWeightMatchingCoveringWith[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], required []int64) (*WeightedMatchingResult[W], error) {
+	if err := validateEdges(ar, edges); err != nil {
+		return nil, err
+	}
+	if err := validateRequired(required); err != nil {
+		return nil, err
+	}
+	return solveCovering(mwm, ar, edges, required)
+}
+
+// validateRequired checks that all required vertices are non-negative
+func validateRequired(required []int64) error {
+	for _, v := range required {
+		if v < 0 {
+			return fmt.Errorf("%w: required vertex %d", ErrNegativeVertex, v)
+		}
+	}
+	return nil
+}
+
+// solveCovering solves the problem on validated edges with a bonus for every covered required vertex
+func solveCovering[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], required []int64) (result *WeightedMatchingResult[W], err error) {
+	defer recoverFailure(&err)
+
+	isRequired := make(map[int64]bool, len(required))
+	for _, v := range required {
+		isRequired[v] = true
+	}
+	total := ar.Zero()
+	for _, edge := range edges {
+		if ar.Cmp(edge.Weight, ar.Zero()) < 0 {
+			total = ar.Sub(total, edge.Weight)
+		} else {
+			total = ar.Add(total, edge.Weight)
+		}
+	}
+
+	var sol *solution[W]
+	if ar.Cmp(total, ar.Zero()) == 0 {
+		// All weights count as zero, so the bonus alone decides and W need not be able to express it
+		counted := make([]GraphEdge, len(edges))
+		for k, edge := range edges {
+			counted[k] = GraphEdge{Node1: edge.Node1, Node2: edge.Node2}
+			for _, v := range []int64{edge.Node1, edge.Node2} {
+				if isRequired[v] {
+					counted[k].Weight++
+				}
+			}
+		}
+		countedSol := maxWeightMatchingInternal(mwm, Int64Arithmetic{}, counted, solveOptions{}, nil)
+		sol = &solution[W]{mate: countedSol.mate, mateedge: countedSol.mateedge, interrupted: countedSol.interrupted, stats: countedSol.stats}
+	} else {
+		bonus := ar.Double(total)
+		boosted := make([]WeightedEdge[W], len(edges))
+		for k, edge := range edges {
+			boosted[k] = edge
+			for _, v := range []int64{edge.Node1, edge.Node2} {
+				if isRequired[v] {
+					boosted[k].Weight = ar.Add(boosted[k].Weight, bonus)
+				}
+			}
+		}
+		sol = maxWeightMatchingInternal(mwm, ar, boosted, solveOptions{}, nil)
+		sol.vertexDuals, sol.blossoms = nil, nil
+	}
+
+	result = newMatchingResult(ar, edges, sol, false)
+	if sol.interrupted != nil {
+		return result, sol.interrupted
+	}
+	// Deleting reported vertices from isRequired lists repeated ones once
+	uncovered := make([]int64, 0)
+	for _, v := range required {
+		if (v >= int64(len(sol.mate)) || sol.mate[v] == -1) && isRequired[v] {
+			uncovered = append(uncovered, v)
+			delete(isRequired, v)
+		}
+	}
+	if len(uncovered) > 0 {
+		return result, &CoverageError{Uncovered: uncovered}
+	}
+	return result, nil
+}
@@ -0,0 +1,119 @@
+package mwm
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// bruteForceCovering - helper function enumerating every matching among a few edges.
+// Returns the largest number of covered required vertices and the best weight among those matchings
+func bruteForceCovering(edges []GraphEdge, required map[int64]bool) (int, int64) {
+	bestCovered, bestWeight := 0, int64(0)
+	forEachMatching(edges, func(_ int, covered map[int64]bool, _ int, weight int64) {
+		count := 0
+		for v := range required {
+			if covered[v] {
+				count++
+			}
+		}
+		if count > bestCovered || (count == bestCovered && weight > bestWeight) {
+			bestCovered, bestWeight = count, weight
+		}
+	})
+	return bestCovered, bestWeight
+}
+
+// TestCoveringRandom - test random required vertices against exhaustive search
+func TestCoveringRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(23))
+	matcher := NewMaximumWeightedMatching()
+	for i := 0; i < 400; i++ {
+		nvertex := 2 + rng.Intn(7)
+		edges := randomEdges(rng, nvertex, 0.5, -30, 30)
+		if len(edges) > 14 {
+			edges = edges[:14]
+		}
+		required := make([]int64, 0)
+		isRequired := make(map[int64]bool)
+		for v := int64(0); v < int64(nvertex); v++ {
+			if rng.Intn(3) == 0 {
+				required = append(required, v)
+				isRequired[v] = true
+			}
+		}
+
+		covered, weight := bruteForceCovering(edges, isRequired)
+		result, err := matcher.MaxWeightMatchingCovering(edges, required)
+		var coverageErr *CoverageError
+		uncovered := 0
+		if errors.As(err, &coverageErr) {
+			uncovered = len(coverageErr.Uncovered)
+		} else if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if len(required)-uncovered != covered || result.TotalWeight != weight {
+			t.Fatalf("Iteration %d: expected %d covered vertices and weight %d, got %d and %d\nedges: %v\nrequired: %v",
+				i, covered, weight, len(required)-uncovered, result.TotalWeight, edges, required)
+		}
+		for _, v := range required {
+			isUncovered := v >= int64(len(result.Mate)) || result.Mate[v] == -1
+			if isUncovered && (coverageErr == nil || !containsVertex(coverageErr.Uncovered, v)) {
+				t.Fatalf("Iteration %d: uncovered vertex %d is not reported", i, v)
+			}
+		}
+	}
+}
+
+// containsVertex - helper function reporting whether v is in vertices
+func containsVertex(vertices []int64, v int64) bool {
+	for _, w := range vertices {
+		if w == v {
+			return true
+		}
+	}
+	return false
+}
+
+// TestCoveringZeroWeights - test that required vertices are covered when all weights are zero
+func TestCoveringZeroWeights(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []FloatGraphEdge{{Node1: 0, Node2: 1}, {Node1: 1, Node2: 2}, {Node1: 2, Node2: 3}}
+	result, err := MaxWeightMatchingCoveringWith(matcher, Float64Arithmetic{Epsilon: DefaultEpsilon}, edges, []int64{0, 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Mate[0] != 1 || result.Mate[3] != 2 {
+		t.Errorf("Expected edges 0 -- 1 and 2 -- 3, got %v", result.Pairs)
+	}
+}
+
+// TestCoveringErrors - test that uncoverable and invalid required vertices are reported
+func TestCoveringErrors(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{{Node1: 0, Node2: 1, Weight: 5}, {Node1: 1, Node2: 2, Weight: 3}}
+	result, err := matcher.MaxWeightMatchingCovering(edges, []int64{0, 2, 7, 2})
+	var coverageErr *CoverageError
+	if !errors.As(err, &coverageErr) || !errors.Is(err, ErrUncoveredVertex) {
+		t.Fatalf("Expected a CoverageError, got %v", err)
+	}
+	if len(coverageErr.Uncovered) != 2 || coverageErr.Uncovered[1] != 7 || result.Cardinality != 1 {
+		t.Errorf("Expected one uncovered vertex of 0 and 2, and vertex 7, got %v with %v", coverageErr.Uncovered, result.Pairs)
+	}
+	if _, err := matcher.MaxWeightMatchingCovering(edges, []int64{-1}); !errors.Is(err, ErrNegativeVertex) {
+		t.Errorf("Expected ErrNegativeVertex, got %v", err)
+	}
+}
+
+// TestCoveringLargeWeights - test that a bonus beyond the int64 range falls back to big integers
+func TestCoveringLargeWeights(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{{Node1: 0, Node2: 1, Weight: SafeWeightLimit}, {Node1: 1, Node2: 2, Weight: 1}}
+	result, err := matcher.MaxWeightMatchingCovering(edges, []int64{2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Mate[2] != 1 || result.TotalWeight != 1 {
+		t.Errorf("Expected edge 1 -- 2 of weight 1, got %v of weight %d", result.Pairs, result.TotalWeight)
+	}
+}
@@ -0,0 +1,117 @@
+package mwm
+
+import "math/big"
+
+// runScaling solves integer weights by bit scaling, leaving the final state in e like run. The weights are halved, rounding toward
+// zero, until they lie in {-1, 0, 1}. The coarsest weights are solved from scratch, and every finer
+// scale is warm started from the matching of the previous scale. Its duals, with the blossom duals
+// moved into the vertex duals, are doubled plus one, which keeps them feasible because a weight is
+// at most twice its halved weight plus one. The last scale solves the weights themselves, so the
+// result is optimal and carries the certificate of that run.
+//
+// This is a scaling driver around the Edmonds algorithm for warm start experiments, not the
+// algorithm of Gabow and Tarjan: every scale is solved by the scan engine, and one engine with its
+// memory serves all scales. The statistics of all scales are summed. It reports false, without touching e, for
+// arithmetics whose weights are not integers
+func (e *engine[W]) runScaling(mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], opts solveOptions) bool {
+	var one W
+	// scale returns w divided by 2^shift, rounded toward zero like Half
+	var scale func(w W, shift int) W
+	switch any(ar).(type) {
+	case Int32Arithmetic:
+		one = any(int32(1)).(W)
+		scale = func(w W, shift int) W {
+			v := any(w).(int32)
+			if v < 0 {
+				return any(-int32(uint32(-v) >> shift)).(W)
+			}
+			return any(v >> shift).(W)
+		}
+	case Int64Arithmetic:
+		one = any(int64(1)).(W)
+		scale = func(w W, shift int) W {
+			v := any(w).(int64)
+			if v < 0 {
+				return any(-int64(uint64(-v) >> shift)).(W)
+			}
+			return any(v >> shift).(W)
+		}
+	case BigIntArithmetic:
+		one = any(big.NewInt(1)).(W)
+		scale = func(w W, shift int) W {
+			v := any(w).(*big.Int)
+			return any(new(big.Int).Quo(v, new(big.Int).Lsh(big.NewInt(1), uint(shift)))).(W)
+		}
+	default:
+		return false
+	}
+
+	// Every halving step is one scale; nscales halvings turn all weights into 0
+	nscales := 0
+	for _, edge := range edges {
+		s := 0
+		for w := edge.Weight; ar.Cmp(w, ar.Zero()) != 0; w, _ = ar.Half(w) {
+			s++
+		}
+		nscales = max(nscales, s)
+	}
+
+	if nscales == 0 {
+		// Zero weights have no scales
+		e.init(mwm, ar, edges, opts)
+		e.run()
+		return true
+	}
+
+	scaled := make([]WeightedEdge[W], len(edges))
+	var stats *SolverStats
+	var start *WarmStart[W]
+	for shift := nscales - 1; ; shift-- {
+		if shift == 0 {
+			scaled = edges
+		} else {
+			for k, edge := range edges {
+				scaled[k] = WeightedEdge[W]{Node1: edge.Node1, Node2: edge.Node2, Weight: scale(edge.Weight, shift)}
+			}
+		}
+
+		e.init(mwm, ar, scaled, opts)
+		if start != nil {
+			e.seed(start)
+		}
+		e.run()
+		if e.stats != nil {
+			stats = stats.add(e.stats)
+		}
+		if e.interrupted != nil || shift == 0 {
+			break
+		}
+		start = e.scaledWarmStart(start, one)
+	}
+	e.stats = stats
+	return true
+}
+
+// scaledWarmStart returns the warm start of the next finer scale from the solved scale, reusing
+// the memory of the previous warm start if there is one
+func (e *engine[W]) scaledWarmStart(start *WarmStart[W], one W) *WarmStart[W] {
+	ar := e.ar
+	if start == nil {
+		start = &WarmStart[W]{}
+	}
+	start.Mate = append(start.Mate[:0], e.mate[:e.nvertex]...)
+	duals := append(start.VertexDuals[:0], e.dualvar[:e.nvertex]...)
+	for b := e.nvertex; b < 2*e.nvertex; b++ {
+		if e.blossombase[b] >= 0 && ar.Cmp(e.dualvar[b], ar.Zero()) != 0 {
+			e.leaves = e.appendLeaves(e.leaves[:0], b)
+			for _, v := range e.leaves {
+				duals[v] = ar.Add(duals[v], e.dualvar[b])
+			}
+		}
+	}
+	for v, d := range duals {
+		duals[v] = ar.Add(ar.Double(d), one)
+	}
+	start.VertexDuals = duals
+	return start
+}
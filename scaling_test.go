@@ -0,0 +1,167 @@
+package mwm
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// checkScaling - helper function comparing the scaling mode with the plain algorithm on one graph
+func checkScaling(t *testing.T, i int, edges []GraphEdge, maxCardinality bool) {
+	t.Helper()
+	plain := NewMaximumWeightedMatching()
+	expected, err := plain.MaxWeightMatchingE(edges, maxCardinality)
+	if err != nil {
+		t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+	}
+	scaling := NewMaximumWeightedMatching()
+	scaling.Scaling = true
+	result, err := scaling.MaxWeightMatchingE(edges, maxCardinality)
+	if err != nil {
+		t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+	}
+	// Without maximum cardinality, zero-weight edges may be matched or not
+	if result.TotalWeight != expected.TotalWeight || (maxCardinality && result.Cardinality != expected.Cardinality) {
+		t.Fatalf("Iteration %d: expected %d edges of weight %d, got %d edges of weight %d\nedges: %v",
+			i, expected.Cardinality, expected.TotalWeight, result.Cardinality, result.TotalWeight, edges)
+	}
+	if err := VerifyOptimum(edges, result); err != nil {
+		t.Fatalf("Iteration %d: invalid certificate: %v\nedges: %v", i, err, edges)
+	}
+}
+
+// TestScalingRandom - test the scaling mode against the plain algorithm on random graphs with small and large weights
+func TestScalingRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(36))
+	for i := 0; i < 1000; i++ {
+		maxWeight := int64(1) << rng.Intn(40)
+		edges := randomEdges(rng, 2+rng.Intn(40), 0.05+0.5*rng.Float64(), -maxWeight/3, maxWeight)
+		checkScaling(t, i, edges, i%2 == 1)
+	}
+}
+
+// TestScalingBruteForce - test the scaling mode on small graphs against exhaustive search
+func TestScalingBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(37))
+	matcher := NewMaximumWeightedMatching()
+	matcher.Scaling = true
+	for i := 0; i < 500; i++ {
+		nvertex := 2 + rng.Intn(9)
+		maxCardinality := i%2 == 1
+		edges := randomEdges(rng, nvertex, 0.6, -20, 50)
+		cardinality, weight := bruteForceMatching(nvertex, edges, maxCardinality)
+		result, err := matcher.MaxWeightMatchingE(edges, maxCardinality)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if result.TotalWeight != weight || (maxCardinality && result.Cardinality != cardinality) {
+			t.Fatalf("Iteration %d: expected %d edges of weight %d, got %d edges of weight %d\nedges: %v",
+				i, cardinality, weight, result.Cardinality, result.TotalWeight, edges)
+		}
+	}
+}
+
+// TestScalingLargeWeights - test the scaling mode with weights beyond SafeWeightLimit, which are solved with big integers
+func TestScalingLargeWeights(t *testing.T) {
+	rng := rand.New(rand.NewSource(38))
+	for i := 0; i < 50; i++ {
+		// At most four pairs keep the total weight within int64
+		edges := randomEdges(rng, 2+rng.Intn(7), 0.6, 1, 1000000)
+		for k := range edges {
+			edges[k].Weight += SafeWeightLimit
+		}
+		checkScaling(t, i, edges, false)
+	}
+}
+
+// TestScalingArithmetics - test the scaling mode with int32 weights and that float weights are solved as usual
+func TestScalingArithmetics(t *testing.T) {
+	rng := rand.New(rand.NewSource(39))
+	plain := NewMaximumWeightedMatching()
+	matcher := NewMaximumWeightedMatching()
+	matcher.Scaling = true
+	for i := 0; i < 100; i++ {
+		edges := randomEdges(rng, 2+rng.Intn(30), 0.3, -100, 1000)
+		expected, err := plain.MaxWeightMatchingE(edges, false)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		edges32 := make([]WeightedEdge[int32], len(edges))
+		floats := make([]FloatGraphEdge, len(edges))
+		for k, edge := range edges {
+			edges32[k] = WeightedEdge[int32]{Node1: edge.Node1, Node2: edge.Node2, Weight: int32(edge.Weight)}
+			floats[k] = FloatGraphEdge{Node1: edge.Node1, Node2: edge.Node2, Weight: float64(edge.Weight) / 4}
+		}
+		result32, err := MaxWeightMatchingWith(matcher, Int32Arithmetic{}, edges32, false)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if int64(result32.TotalWeight) != expected.TotalWeight {
+			t.Fatalf("Iteration %d: expected weight %d, got %d", i, expected.TotalWeight, result32.TotalWeight)
+		}
+		ar := Float64Arithmetic{Epsilon: DefaultEpsilon}
+		resultFloat, err := MaxWeightMatchingWith(matcher, ar, floats, false)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if ar.Cmp(resultFloat.TotalWeight, float64(expected.TotalWeight)/4) != 0 {
+			t.Fatalf("Iteration %d: expected weight %v, got %v", i, float64(expected.TotalWeight)/4, resultFloat.TotalWeight)
+		}
+	}
+}
+
+// TestScalingStats - test that the statistics of all scales are summed
+func TestScalingStats(t *testing.T) {
+	edges := []GraphEdge{{Node1: 0, Node2: 1, Weight: 6}, {Node1: 1, Node2: 2, Weight: 7}, {Node1: 2, Node2: 3, Weight: 5}}
+	matcher := NewMaximumWeightedMatching()
+	matcher.CollectStats = true
+	expected, err := matcher.MaxWeightMatchingE(edges, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	matcher.Scaling = true
+	result, err := matcher.MaxWeightMatchingE(edges, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.TotalWeight != 11 {
+		t.Errorf("Expected weight 11, got %d", result.TotalWeight)
+	}
+	// The scales with weights {1, 1, 1}, {3, 3, 2} and {6, 7, 5} take more stages than a single run
+	if result.Stats == nil || result.Stats.Stages <= expected.Stats.Stages {
+		t.Errorf("Expected more than %d stages, got %v", expected.Stats.Stages, result.Stats)
+	}
+}
+
+// TestScalingPriorityQueueEngine - test that the priority queue engine ignores Scaling
+func TestScalingPriorityQueueEngine(t *testing.T) {
+	rng := rand.New(rand.NewSource(40))
+	edges := randomEdges(rng, 200, 0.05, 1, 1000000)
+	matcher := NewMaximumWeightedMatching()
+	matcher.Engine = PriorityQueueEngine
+	expected, err := matcher.MaxWeightMatchingE(edges, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	matcher.Scaling = true
+	result, err := matcher.MaxWeightMatchingE(edges, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected the unscaled result %v, got %v", expected.Pairs, result.Pairs)
+	}
+}
+
+// BenchmarkScaling - benchmark the scaling mode on the graph of BenchmarkScanEngine
+func BenchmarkScaling(b *testing.B) {
+	rng := rand.New(rand.NewSource(35))
+	edges := randomEdges(rng, 3000, 0.002, 1, 1000000)
+	matcher := NewMaximumWeightedMatching()
+	matcher.Scaling = true
+	for b.Loop() {
+		if _, err := matcher.MaxWeightMatchingE(edges, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package mwm
+
+import "time"
+
+// SolverStats describes the work done by a run of the algorithm
+type SolverStats struct {
+	// Stages is the number of stages started; every stage but the last one ends with an augmentation
+	Stages int
+	// Substages is the number of times the queue of S-vertices was scanned
+	Substages int
+	// Augmentations is the number of augmenting paths, each of which adds one edge to the matching
+	Augmentations int
+	// BlossomsCreated is the number of blossoms formed
+	BlossomsCreated int
+	// BlossomsExpanded is the number of blossoms expanded, during a stage or at its end
+	BlossomsExpanded int
+	// DeltaTypes counts how often each delta type (1-4) was chosen for a dual update; index 0 is unused
+	DeltaTypes [5]int
+
+	// SetupTime is spent building the graph structures and initial duals
+	SetupTime time.Duration
+	// ScanTime is spent labeling vertices, scanning edges, forming blossoms and augmenting
+	ScanTime time.Duration
+	// DualUpdateTime is spent choosing delta and updating the dual variables
+	DualUpdateTime time.Duration
+	// ExpandTime is spent expanding blossoms
+	ExpandTime time.Duration
+	// FinishTime is spent extracting the matching and the dual solution
+	FinishTime time.Duration
+	// TotalTime is the wall time of the whole run
+	TotalTime time.Duration
+}
+
+// add returns the sum of the statistics s, which may be nil, and o
+func (s *SolverStats) add(o *SolverStats) *SolverStats {
+	if s == nil {
+		sum := *o
+		return &sum
+	}
+	s.Stages += o.Stages
+	s.Substages += o.Substages
+	s.Augmentations += o.Augmentations
+	s.BlossomsCreated += o.BlossomsCreated
+	s.BlossomsExpanded += o.BlossomsExpanded
+	for t := range s.DeltaTypes {
+		s.DeltaTypes[t] += o.DeltaTypes[t]
+	}
+	s.SetupTime += o.SetupTime
+	s.ScanTime += o.ScanTime
+	s.DualUpdateTime += o.DualUpdateTime
+	s.ExpandTime += o.ExpandTime
+	s.FinishTime += o.FinishTime
+	s.TotalTime += o.TotalTime
+	return s
+}
@@ -0,0 +1,51 @@
+package mwm
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestSolverStats - test that the counters and timings are consistent with the result
+func TestSolverStats(t *testing.T) {
+	rng := rand.New(rand.NewSource(13))
+	matcher := NewMaximumWeightedMatching()
+	matcher.CollectStats = true
+	for i := 0; i < 100; i++ {
+		edges := randomEdges(rng, 2+rng.Intn(40), 0.3, -10, 100)
+		result := matcher.MaxWeightMatchingResult(edges, i%2 == 1)
+		stats := result.Stats
+		if stats == nil {
+			t.Fatalf("Iteration %d: expected stats", i)
+		}
+		if stats.Augmentations != result.Cardinality {
+			t.Fatalf("Iteration %d: expected %d augmentations, got %d", i, result.Cardinality, stats.Augmentations)
+		}
+		if stats.BlossomsCreated-stats.BlossomsExpanded != len(result.Blossoms) {
+			t.Fatalf("Iteration %d: %d blossoms created, %d expanded, %d remaining",
+				i, stats.BlossomsCreated, stats.BlossomsExpanded, len(result.Blossoms))
+		}
+		if stats.Substages < stats.Stages || stats.Stages < stats.Augmentations {
+			t.Fatalf("Iteration %d: %d stages, %d substages, %d augmentations", i, stats.Stages, stats.Substages, stats.Augmentations)
+		}
+		deltas := 0
+		for _, count := range stats.DeltaTypes {
+			deltas += count
+		}
+		if stats.DeltaTypes[0] != 0 || deltas > stats.Substages {
+			t.Fatalf("Iteration %d: delta types %v for %d substages", i, stats.DeltaTypes, stats.Substages)
+		}
+		phases := stats.SetupTime + stats.ScanTime + stats.DualUpdateTime + stats.ExpandTime + stats.FinishTime
+		if phases != stats.TotalTime {
+			t.Fatalf("Iteration %d: phases take %v, total %v", i, phases, stats.TotalTime)
+		}
+	}
+}
+
+// TestSolverStatsDisabled - test that no stats are reported unless requested
+func TestSolverStatsDisabled(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	result := matcher.MaxWeightMatchingResult([]GraphEdge{{Node1: 0, Node2: 1, Weight: 1}}, false)
+	if result.Stats != nil {
+		t.Errorf("Expected no stats, got %+v", result.Stats)
+	}
+}
@@ -0,0 +1,146 @@
+package mwm
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Tracer observes the steps of the algorithm. Vertices are numbered as in the edges,
+// blossoms are numbered from the number of vertices upwards, and edges are indices into the input slice.
+// Embed NopTracer to implement only some of the callbacks
+type Tracer interface {
+	// OnStage is called at the start of every stage
+	OnStage(stage int)
+	// OnSubstage is called at the start of every substage, before the queue is scanned
+	OnSubstage()
+	// OnAssignLabel is called when vertex receives label 1 (S) or 2 (T) through the given edge endpoint, or -1
+	OnAssignLabel(vertex, label, endpoint int)
+	// OnAddBlossom is called when a new blossom with the given base vertex is formed by edge
+	OnAddBlossom(blossom, base, edge int)
+	// OnExpandBlossom is called when a blossom is expanded, during a stage or at its end
+	OnExpandBlossom(blossom int, endstage bool)
+	// OnAugment is called when the matching is augmented along a path through edge (v, w)
+	OnAugment(edge, v, w int)
+	// OnDualUpdate is called after the dual variables were changed by delta, chosen by the given delta type (1-4)
+	OnDualUpdate(deltatype int, delta any)
+	// OnFinish is called with the final mate of every vertex, or -1
+	OnFinish(mate []int64)
+}
+
+// NopTracer implements Tracer with callbacks that do nothing
+type NopTracer struct{}
+
+func (NopTracer) OnStage(stage int)                          {}
+func (NopTracer) OnSubstage()                                {}
+func (NopTracer) OnAssignLabel(vertex, label, endpoint int)  {}
+func (NopTracer) OnAddBlossom(blossom, base, edge int)       {}
+func (NopTracer) OnExpandBlossom(blossom int, endstage bool) {}
+func (NopTracer) OnAugment(edge, v, w int)                   {}
+func (NopTracer) OnDualUpdate(deltatype int, delta any)      {}
+func (NopTracer) OnFinish(mate []int64)                      {}
+
+// SlogTracer logs the algorithm steps as structured events at debug level.
+// Every event carries the current stage as attribute
+type SlogTracer struct {
+	Logger *slog.Logger
+	// Context is passed to the handler; context.Background() is used when nil
+	Context context.Context
+	stage   int
+}
+
+// NewSlogTracer creates a tracer logging to logger
+func NewSlogTracer(logger *slog.Logger) *SlogTracer {
+	return &SlogTracer{Logger: logger, stage: -1}
+}
+
+func (st *SlogTracer) log(msg string, attrs ...slog.Attr) {
+	ctx := st.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	st.Logger.LogAttrs(ctx, slog.LevelDebug, msg, append(attrs, slog.Int("stage", st.stage))...)
+}
+
+func (st *SlogTracer) OnStage(stage int) {
+	st.stage = stage
+	st.log("stage")
+}
+
+func (st *SlogTracer) OnSubstage() {
+	st.log("substage")
+}
+
+func (st *SlogTracer) OnAssignLabel(vertex, label, endpoint int) {
+	st.log("assign label", slog.Int("vertex", vertex), slog.Int("label", label), slog.Int("endpoint", endpoint))
+}
+
+func (st *SlogTracer) OnAddBlossom(blossom, base, edge int) {
+	st.log("add blossom", slog.Int("blossom", blossom), slog.Int("base", base), slog.Int("edge", edge))
+}
+
+func (st *SlogTracer) OnExpandBlossom(blossom int, endstage bool) {
+	st.log("expand blossom", slog.Int("blossom", blossom), slog.Bool("endstage", endstage))
+}
+
+func (st *SlogTracer) OnAugment(edge, v, w int) {
+	st.log("augment matching", slog.Int("edge", edge), slog.Int("v", v), slog.Int("w", w))
+}
+
+func (st *SlogTracer) OnDualUpdate(deltatype int, delta any) {
+	st.log("dual update", slog.Int("deltatype", deltatype), slog.Any("delta", delta))
+}
+
+func (st *SlogTracer) OnFinish(mate []int64) {
+	st.log("matching", slog.Any("mate", mate))
+}
+
+// tracers forwards every callback to several tracers
+type tracers []Tracer
+
+func (ts tracers) OnStage(stage int) {
+	for _, t := range ts {
+		t.OnStage(stage)
+	}
+}
+
+func (ts tracers) OnSubstage() {
+	for _, t := range ts {
+		t.OnSubstage()
+	}
+}
+
+func (ts tracers) OnAssignLabel(vertex, label, endpoint int) {
+	for _, t := range ts {
+		t.OnAssignLabel(vertex, label, endpoint)
+	}
+}
+
+func (ts tracers) OnAddBlossom(blossom, base, edge int) {
+	for _, t := range ts {
+		t.OnAddBlossom(blossom, base, edge)
+	}
+}
+
+func (ts tracers) OnExpandBlossom(blossom int, endstage bool) {
+	for _, t := range ts {
+		t.OnExpandBlossom(blossom, endstage)
+	}
+}
+
+func (ts tracers) OnAugment(edge, v, w int) {
+	for _, t := range ts {
+		t.OnAugment(edge, v, w)
+	}
+}
+
+func (ts tracers) OnDualUpdate(deltatype int, delta any) {
+	for _, t := range ts {
+		t.OnDualUpdate(deltatype, delta)
+	}
+}
+
+func (ts tracers) OnFinish(mate []int64) {
+	for _, t := range ts {
+		t.OnFinish(mate)
+	}
+}
@@ -0,0 +1,88 @@
+package mwm
+
+import (
+	"bytes"
+	"log/slog"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// countingTracer - helper tracer counting the callbacks it receives
+type countingTracer struct {
+	NopTracer
+	stages       []int
+	augments     int
+	addBlossoms  int
+	expansions   int
+	deltatypes   map[int]int
+	mate         []int64
+	invalidDelta bool
+}
+
+func (ct *countingTracer) OnStage(stage int)                    { ct.stages = append(ct.stages, stage) }
+func (ct *countingTracer) OnAugment(edge, v, w int)             { ct.augments++ }
+func (ct *countingTracer) OnAddBlossom(blossom, base, edge int) { ct.addBlossoms++ }
+func (ct *countingTracer) OnFinish(mate []int64)                { ct.mate = append([]int64(nil), mate...) }
+
+func (ct *countingTracer) OnExpandBlossom(blossom int, endstage bool) {
+	ct.expansions++
+}
+
+func (ct *countingTracer) OnDualUpdate(deltatype int, delta any) {
+	ct.deltatypes[deltatype]++
+	if _, ok := delta.(int64); !ok {
+		ct.invalidDelta = true
+	}
+}
+
+// TestTracer - test that a tracer observes stages, augmentations, blossoms and dual updates
+func TestTracer(t *testing.T) {
+	rng := rand.New(rand.NewSource(12))
+	for i := 0; i < 50; i++ {
+		tracer := &countingTracer{deltatypes: make(map[int]int)}
+		matcher := NewMaximumWeightedMatching()
+		matcher.Tracer = tracer
+		edges := randomEdges(rng, 2+rng.Intn(30), 0.3, -10, 100)
+		result := matcher.MaxWeightMatchingResult(edges, i%2 == 1)
+
+		if tracer.augments != result.Cardinality {
+			t.Fatalf("Iteration %d: expected %d augmentations, got %d", i, result.Cardinality, tracer.augments)
+		}
+		for k, stage := range tracer.stages {
+			if stage != k {
+				t.Fatalf("Iteration %d: expected stage %d, got %d", i, k, stage)
+			}
+		}
+		if len(edges) > 0 && !reflect.DeepEqual(tracer.mate, result.Mate) {
+			t.Fatalf("Iteration %d: expected final mate %v, got %v", i, result.Mate, tracer.mate)
+		}
+		if tracer.expansions > tracer.addBlossoms || len(result.Blossoms) != tracer.addBlossoms-tracer.expansions {
+			t.Fatalf("Iteration %d: %d blossoms added, %d expanded, %d remaining", i, tracer.addBlossoms, tracer.expansions, len(result.Blossoms))
+		}
+		for deltatype := range tracer.deltatypes {
+			if deltatype < 1 || deltatype > 4 {
+				t.Fatalf("Iteration %d: invalid delta type %d", i, deltatype)
+			}
+		}
+		if tracer.invalidDelta {
+			t.Fatalf("Iteration %d: delta is not an int64", i)
+		}
+	}
+}
+
+// TestTracerWithLogger - test that the tracer and the logger both observe the algorithm
+func TestTracerWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	tracer := &countingTracer{deltatypes: make(map[int]int)}
+	matcher := NewMaximumWeightedMatching()
+	matcher.Tracer = tracer
+	matcher.Logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	matcher.MaxWeightMatching([]GraphEdge{{Node1: 0, Node2: 1, Weight: 1}}, false)
+	if tracer.augments != 1 {
+		t.Errorf("Expected 1 augmentation, got %d", tracer.augments)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("augment matching")) {
+		t.Errorf("Expected a logged augmentation, got %q", buf.String())
+	}
+}
@@ -0,0 +1,318 @@
+package mwm
+
+import "time"
+
+// MaxCardinalityMatching returns a matching with the largest number of edges, ignoring the weights.
+// Bipartite graphs are solved by the Hopcroft-Karp algorithm in O(m sqrt(n)). Other graphs start
+// from a greedy matching and grow it by one breadth-first search for an augmenting path per free
+// vertex, which shrinks blossoms with a disjoint-set forest and only touches the vertices it reaches,
+// in O(n m α(n)). The O(m sqrt(n)) bound is scoped to bipartite graphs: an O(m sqrt(n)) algorithm for
+// general graphs, such as the one of Micali and Vazirani, is out of scope of this package. TotalWeight
+// and Weights report the weights of the matched edges, and the result carries no dual solution.
+//
+// MaxWeightMatching routes graphs whose edges all have the same weight here, when that weight is
+// positive or maxCardinality is set, since every maximum cardinality matching is then optimal
+func (mwm *MaximumWeightedMatching) MaxCardinalityMatching(edges []GraphEdge) (*MatchingResult, error) {
+	if err := validateEdges(Int64Arithmetic{}, edges); err != nil {
+		return nil, err
+	}
+	return MaxCardinalityMatchingWith(mwm, Int64Arithmetic{}, edges)
+}
+
+// MaxCardinalityMatchingWith is MaxCardinalityMatching for weights of type W, adding the matched weights with ar
+func MaxCardinalityMatchingWith[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W]) (result *WeightedMatchingResult[W], err error) {
+	if err := validateEdges(ar, edges); err != nil {
+		return nil, err
+	}
+	defer recoverFailure(&err)
+
+	return newMatchingResult(ar, edges, cardinalitySolution(mwm, edges), true), nil
+}
+
+// cardinalitySolution computes a maximum cardinality matching of validated edges
+func cardinalitySolution[W any](mwm *MaximumWeightedMatching, edges []WeightedEdge[W]) *solution[W] {
+	start := time.Now()
+	g := newUnweightedGraph(edges)
+	g.greedy()
+	if isLeft, err := bipartition(edges, nil); err == nil {
+		g.hopcroftKarp(isLeft)
+	} else {
+		for v := range g.mate {
+			if g.mate[v] == -1 {
+				g.augmentFrom(v)
+			}
+		}
+	}
+
+	sol := graphSolution[W](g)
+	if mwm.CollectStats {
+		sol.stats = &SolverStats{Augmentations: g.augmentations, TotalTime: time.Since(start)}
+		sol.stats.ScanTime = sol.stats.TotalTime
+	}
+	return sol
+}
+
+// graphSolution returns the matching of g as a solution without duals
+func graphSolution[W any](g *unweightedGraph) *solution[W] {
+	sol := &solution[W]{mate: make([]int64, g.nvertex), mateedge: make([]int, g.nvertex)}
+	for v, w := range g.mate {
+		sol.mate[v] = int64(w)
+		sol.mateedge[v] = g.mateedge[v]
+	}
+	return sol
+}
+
+// unweightedGraph holds a graph in adjacency form and the matching grown on it
+type unweightedGraph struct {
+	nvertex int
+	// incident[incidentstart[v]:incidentstart[v+1]] lists the edges of vertex v, and
+	// neighbor[p] the other end of the edge incident[p]
+	incidentstart []int
+	incident      []int
+	neighbor      []int
+	mate          []int
+	mateedge      []int
+	augmentations int
+
+	// Search state of the blossom algorithm: label is -1 before a vertex is reached, 0 for even
+	// and 1 for odd vertices; parent is the vertex an odd vertex was reached from, or the other
+	// end of the crossing edge of a blossom, and parentedge the edge between them; base holds
+	// the disjoint-set forest of blossom bases; reached lists the vertices whose label and base
+	// the last search changed, which are the only ones the next search resets
+	label      []int
+	parent     []int
+	parentedge []int
+	base       []int
+	mark       []int
+	stamp      int
+	queue      []int
+	reached    []int
+}
+
+func newUnweightedGraph[W any](edges []WeightedEdge[W]) *unweightedGraph {
+	nvertex := 0
+	for _, edge := range edges {
+		nvertex = max(nvertex, int(edge.Node1)+1, int(edge.Node2)+1)
+	}
+	g := &unweightedGraph{
+		nvertex:       nvertex,
+		incidentstart: make([]int, nvertex+1),
+		incident:      make([]int, 2*len(edges)),
+		neighbor:      make([]int, 2*len(edges)),
+		mate:          make([]int, nvertex),
+		mateedge:      make([]int, nvertex),
+	}
+	for _, edge := range edges {
+		g.incidentstart[edge.Node1+1]++
+		g.incidentstart[edge.Node2+1]++
+	}
+	for v := 0; v < nvertex; v++ {
+		g.incidentstart[v+1] += g.incidentstart[v]
+	}
+	fill := append([]int(nil), g.incidentstart[:nvertex]...)
+	for k, edge := range edges {
+		u, v := int(edge.Node1), int(edge.Node2)
+		g.incident[fill[u]], g.neighbor[fill[u]] = k, v
+		fill[u]++
+		g.incident[fill[v]], g.neighbor[fill[v]] = k, u
+		fill[v]++
+	}
+	for v := range g.mate {
+		g.mate[v] = -1
+		g.mateedge[v] = -1
+	}
+	return g
+}
+
+// match pairs u and v through edge k
+func (g *unweightedGraph) match(u, v, k int) {
+	g.mate[u], g.mate[v] = v, u
+	g.mateedge[u], g.mateedge[v] = k, k
+}
+
+// greedy matches every free vertex to its first free neighbor
+func (g *unweightedGraph) greedy() {
+	for u := 0; u < g.nvertex; u++ {
+		for p := g.incidentstart[u]; p < g.incidentstart[u+1] && g.mate[u] == -1; p++ {
+			if v := g.neighbor[p]; g.mate[v] == -1 {
+				g.match(u, v, g.incident[p])
+			}
+		}
+	}
+}
+
+// hopcroftKarp grows the matching by maximal sets of shortest vertex-disjoint augmenting paths
+// from the left vertices, which needs O(sqrt(n)) phases of O(m) each
+func (g *unweightedGraph) hopcroftKarp(isLeft []bool) {
+	const unreached = -1
+	layer := make([]int, g.nvertex)
+	next := make([]int, g.nvertex)
+	queue := make([]int, 0, g.nvertex)
+	// limit is the layer of the left vertices next to the closest free right vertices
+	limit := 0
+
+	// dfs follows the layers from left vertex u to a free right vertex and augments along the path
+	var dfs func(u int) bool
+	dfs = func(u int) bool {
+		for ; next[u] < g.incidentstart[u+1]; next[u]++ {
+			p := next[u]
+			v := g.neighbor[p]
+			w := g.mate[v]
+			if (w == -1 && layer[u] == limit) || (w != -1 && layer[w] == layer[u]+1 && dfs(w)) {
+				g.match(u, v, g.incident[p])
+				return true
+			}
+		}
+		layer[u] = unreached
+		return false
+	}
+
+	for {
+		// Breadth-first search from the free left vertices, numbering the layers of left vertices
+		// up to the first layer with a free right neighbor
+		queue = queue[:0]
+		for u := 0; u < g.nvertex; u++ {
+			layer[u] = unreached
+			if isLeft[u] && g.mate[u] == -1 {
+				layer[u] = 0
+				queue = append(queue, u)
+			}
+		}
+		limit = unreached
+		for i := 0; i < len(queue); i++ {
+			u := queue[i]
+			if limit != unreached && layer[u] > limit {
+				break
+			}
+			for p := g.incidentstart[u]; p < g.incidentstart[u+1]; p++ {
+				w := g.mate[g.neighbor[p]]
+				if w == -1 {
+					limit = layer[u]
+				} else if layer[w] == unreached {
+					layer[w] = layer[u] + 1
+					queue = append(queue, w)
+				}
+			}
+		}
+		if limit == unreached {
+			return
+		}
+
+		for u := 0; u < g.nvertex; u++ {
+			next[u] = g.incidentstart[u]
+		}
+		for u := 0; u < g.nvertex; u++ {
+			if isLeft[u] && g.mate[u] == -1 && dfs(u) {
+				g.augmentations++
+			}
+		}
+	}
+}
+
+// find returns the base of the outermost blossom containing v
+func (g *unweightedGraph) find(v int) int {
+	for g.base[v] != v {
+		g.base[v] = g.base[g.base[v]]
+		v = g.base[v]
+	}
+	return v
+}
+
+// lca returns the base of the blossom closing at the edge between the even vertices u and v
+func (g *unweightedGraph) lca(u, v int) int {
+	g.stamp++
+	for {
+		if u != -1 {
+			u = g.find(u)
+			if g.mark[u] == g.stamp {
+				return u
+			}
+			g.mark[u] = g.stamp
+			if g.mate[u] == -1 {
+				u = -1
+			} else {
+				u = g.parent[g.mate[u]]
+			}
+		}
+		u, v = v, u
+	}
+}
+
+// shrink contracts the path from u up to the blossom base b into the blossom, entering it
+// through edge k to v. Odd vertices on the path become even and are scanned
+func (g *unweightedGraph) shrink(u, v, k, b int) {
+	for g.find(u) != b {
+		g.parent[u], g.parentedge[u] = v, k
+		v = g.mate[u]
+		if g.label[v] == 1 {
+			g.label[v] = 0
+			g.queue = append(g.queue, v)
+		}
+		if g.find(u) == u {
+			g.base[u] = b
+		}
+		if g.find(v) == v {
+			g.base[v] = b
+		}
+		u, k = g.parent[v], g.parentedge[v]
+	}
+}
+
+// augmentFrom searches an augmenting path from the free vertex root and augments along it. The
+// search only resets the vertices that the previous one reached, so it takes O(m' α(n)) for the
+// m' edges of the vertices it reaches
+func (g *unweightedGraph) augmentFrom(root int) bool {
+	if g.label == nil {
+		g.label = make([]int, g.nvertex)
+		g.parent = make([]int, g.nvertex)
+		g.parentedge = make([]int, g.nvertex)
+		g.base = make([]int, g.nvertex)
+		g.mark = make([]int, g.nvertex)
+		for v := 0; v < g.nvertex; v++ {
+			g.label[v] = -1
+			g.base[v] = v
+		}
+	}
+	for _, v := range g.reached {
+		g.label[v] = -1
+		g.base[v] = v
+	}
+	g.label[root] = 0
+	g.reached = append(g.reached[:0], root)
+	g.queue = append(g.queue[:0], root)
+	for i := 0; i < len(g.queue); i++ {
+		u := g.queue[i]
+		for p := g.incidentstart[u]; p < g.incidentstart[u+1]; p++ {
+			v := g.neighbor[p]
+			switch {
+			case g.label[v] == -1:
+				g.label[v] = 1
+				g.parent[v], g.parentedge[v] = u, g.incident[p]
+				g.reached = append(g.reached, v)
+				if g.mate[v] == -1 {
+					g.augment(v)
+					return true
+				}
+				g.label[g.mate[v]] = 0
+				g.reached = append(g.reached, g.mate[v])
+				g.queue = append(g.queue, g.mate[v])
+			case g.label[v] == 0 && g.find(u) != g.find(v):
+				b := g.lca(u, v)
+				g.shrink(u, v, g.incident[p], b)
+				g.shrink(v, u, g.incident[p], b)
+			}
+		}
+	}
+	return false
+}
+
+// augment flips the alternating path that ends at the free vertex v
+func (g *unweightedGraph) augment(v int) {
+	for v != -1 {
+		u := g.parent[v]
+		next := g.mate[u]
+		g.match(u, v, g.parentedge[v])
+		v = next
+	}
+	g.augmentations++
+}
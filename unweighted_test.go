@@ -0,0 +1,134 @@
+package mwm
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// checkCardinalityMatching - helper function comparing a maximum cardinality matching with the
+// blossom algorithm in maximum cardinality mode and checking that its pairs are edges of the graph
+func checkCardinalityMatching(t *testing.T, i int, edges []GraphEdge) {
+	t.Helper()
+	matcher := NewMaximumWeightedMatching()
+	result, err := matcher.MaxCardinalityMatching(edges)
+	if err != nil {
+		t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+	}
+	expected, err := matcher.MaxWeightMatchingE(edges, true)
+	if err != nil {
+		t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+	}
+	if result.Cardinality != expected.Cardinality {
+		t.Fatalf("Iteration %d: expected %d edges, got %d\nedges: %v", i, expected.Cardinality, result.Cardinality, edges)
+	}
+	var total int64
+	for p, pair := range result.Pairs {
+		if result.Mate[pair.First] != pair.Second || result.Mate[pair.Second] != pair.First {
+			t.Fatalf("Iteration %d: inconsistent pair %v", i, pair)
+		}
+		edge := edges[result.EdgeIndices[p]]
+		if min(edge.Node1, edge.Node2) != pair.First || max(edge.Node1, edge.Node2) != pair.Second {
+			t.Fatalf("Iteration %d: pair %v does not match edge %v", i, pair, edge)
+		}
+		total += edge.Weight
+	}
+	if total != result.TotalWeight {
+		t.Fatalf("Iteration %d: expected total weight %d, got %d", i, total, result.TotalWeight)
+	}
+}
+
+// TestMaxCardinalityRandom - test the cardinality of random general and bipartite graphs
+func TestMaxCardinalityRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(29))
+	for i := 0; i < 500; i++ {
+		if i%2 == 0 {
+			checkCardinalityMatching(t, i, randomEdges(rng, 2+rng.Intn(30), 0.02+0.3*rng.Float64(), -5, 5))
+		} else {
+			checkCardinalityMatching(t, i, randomBipartiteEdges(rng, 1+rng.Intn(20), 1+rng.Intn(20), 0.02+0.3*rng.Float64(), -5, 5))
+		}
+	}
+}
+
+// TestMaxCardinalityBlossoms - test graphs where the greedy start needs augmenting paths through blossoms
+func TestMaxCardinalityBlossoms(t *testing.T) {
+	graphs := [][][2]int64{
+		// Triangle with a pendant edge on every corner
+		{{0, 1}, {1, 2}, {2, 0}, {0, 3}, {1, 4}, {2, 5}},
+		// Pentagon with a stem, which greedy matches badly
+		{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 0}, {0, 5}, {5, 6}},
+		// Two triangles joined by a path
+		{{0, 1}, {1, 2}, {2, 0}, {2, 3}, {3, 4}, {4, 5}, {5, 6}, {6, 4}, {1, 7}, {6, 8}},
+		// Nested blossoms
+		{{0, 1}, {0, 2}, {1, 2}, {1, 3}, {2, 4}, {3, 4}, {3, 5}, {4, 6}, {5, 6}, {5, 7}, {7, 8}, {6, 9}},
+	}
+	for i, graph := range graphs {
+		edges := make([]GraphEdge, len(graph))
+		for k, e := range graph {
+			edges[k] = GraphEdge{Node1: e[0], Node2: e[1], Weight: 1}
+		}
+		checkCardinalityMatching(t, i, edges)
+	}
+}
+
+// TestUniformWeightRouting - test that MaxWeightMatching finds optimal matchings for uniform weights
+func TestUniformWeightRouting(t *testing.T) {
+	rng := rand.New(rand.NewSource(30))
+	matcher := NewMaximumWeightedMatching()
+	for i := 0; i < 200; i++ {
+		weight := int64(rng.Intn(5) - 2)
+		maxCardinality := i%2 == 1
+		edges := randomEdges(rng, 2+rng.Intn(25), 0.2, 0, 0)
+		for k := range edges {
+			edges[k].Weight = weight
+		}
+		expected, err := matcher.MaxWeightMatchingE(edges, maxCardinality)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		pairs := matcher.MaxWeightMatching(edges, maxCardinality)
+		if int64(len(pairs))*weight != expected.TotalWeight || (maxCardinality && len(pairs) != expected.Cardinality) {
+			t.Fatalf("Iteration %d: expected %d edges of weight %d, got %d pairs of weight %d",
+				i, expected.Cardinality, expected.TotalWeight, len(pairs), weight)
+		}
+	}
+}
+
+// TestMaxCardinalityInvalid - test that invalid edges are reported
+func TestMaxCardinalityInvalid(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	if _, err := matcher.MaxCardinalityMatching([]GraphEdge{{Node1: 0, Node2: -1, Weight: 1}}); !errors.Is(err, ErrNegativeVertex) {
+		t.Errorf("Expected ErrNegativeVertex, got %v", err)
+	}
+	if _, err := matcher.MaxCardinalityMatching([]GraphEdge{{Node1: 2, Node2: 2, Weight: 1}}); !errors.Is(err, ErrSelfLoop) {
+		t.Errorf("Expected ErrSelfLoop, got %v", err)
+	}
+	result, err := matcher.MaxCardinalityMatching(nil)
+	if err != nil || result.Cardinality != 0 {
+		t.Errorf("Expected an empty matching, got %v with error %v", result, err)
+	}
+}
+
+// BenchmarkMaxCardinality - benchmark the unweighted search on a sparse general graph
+func BenchmarkMaxCardinality(b *testing.B) {
+	rng := rand.New(rand.NewSource(31))
+	edges := randomEdges(rng, 2000, 0.002, 1, 1)
+	matcher := NewMaximumWeightedMatching()
+	for b.Loop() {
+		if _, err := matcher.MaxCardinalityMatching(edges); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkMaxCardinalityBlossom - benchmark the weighted blossom algorithm on the graph of BenchmarkMaxCardinality
+func BenchmarkMaxCardinalityBlossom(b *testing.B) {
+	rng := rand.New(rand.NewSource(31))
+	edges := randomEdges(rng, 2000, 0.002, 1, 1)
+	matcher := NewMaximumWeightedMatching()
+	for b.Loop() {
+		if _, err := matcher.MaxWeightMatchingE(edges, true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package mwm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotOptimal is reported by VerifyOptimum when the result is not a valid optimality certificate
+var ErrNotOptimal = errors.New("mwm: matching is not certified optimal")
+
+// VerifyOptimum checks that result is a valid matching of edges and that its dual
+// solution satisfies the complementary slackness conditions, which proves optimality.
+// In maximum cardinality mode optimality is proven among the matchings with the same number of edges.
+// Ported from verifyOptimum of the Python implementation by Joris van Rantwijk.
+// Weights beyond SafeWeightLimit are checked with big integer arithmetic
+func VerifyOptimum(edges []GraphEdge, result *MatchingResult) error {
+	if withinSafeWeightLimit(edges) {
+		err := VerifyOptimumWith(Int64Arithmetic{}, edges, result)
+		if err != ErrWeightOverflow {
+			return err
+		}
+	}
+	return VerifyOptimumWith(BigIntArithmetic{}, bigEdges(edges), bigResult(result))
+}
+
+// VerifyOptimumWith is VerifyOptimum for weights of type W, comparing values with ar
+func VerifyOptimumWith[W any](ar Arithmetic[W], edges []WeightedEdge[W], result *WeightedMatchingResult[W]) (err error) {
+	defer recoverFailure(&err)
+
+	if err := validateEdges(ar, edges); err != nil {
+		return err
+	}
+
+	nvertex := len(result.VertexDuals)
+	if len(result.Mate) != nvertex {
+		return fmt.Errorf("%w: %d mates for %d vertex duals", ErrNotOptimal, len(result.Mate), nvertex)
+	}
+	if len(result.EdgeIndices) != len(result.Pairs) {
+		return fmt.Errorf("%w: %d edge indices for %d pairs", ErrNotOptimal, len(result.EdgeIndices), len(result.Pairs))
+	}
+
+	// Check that the pairs form a matching on the given edges
+	mateedge := make([]int, nvertex)
+	for v := range mateedge {
+		mateedge[v] = -1
+	}
+	for i, pair := range result.Pairs {
+		k := result.EdgeIndices[i]
+		if k < 0 || k >= len(edges) {
+			return fmt.Errorf("%w: pair %d refers to edge %d", ErrNotOptimal, i, k)
+		}
+		edge := edges[k]
+		if !(edge.Node1 == pair.First && edge.Node2 == pair.Second) && !(edge.Node1 == pair.Second && edge.Node2 == pair.First) {
+			return fmt.Errorf("%w: pair %d does not match edge %d", ErrNotOptimal, i, k)
+		}
+		if edge.Node1 >= int64(nvertex) || edge.Node2 >= int64(nvertex) {
+			return fmt.Errorf("%w: edge %d has no vertex duals", ErrNotOptimal, k)
+		}
+		for _, v := range []int64{edge.Node1, edge.Node2} {
+			if mateedge[v] != -1 {
+				return fmt.Errorf("%w: vertex %d is matched twice", ErrNotOptimal, v)
+			}
+			mateedge[v] = k
+		}
+	}
+	for v, w := range result.Mate {
+		var expected int64 = -1
+		if k := mateedge[v]; k != -1 {
+			expected = edges[k].Node1 + edges[k].Node2 - int64(v)
+		}
+		if w != expected {
+			return fmt.Errorf("%w: mate of vertex %d is %d, expected %d", ErrNotOptimal, v, w, expected)
+		}
+	}
+
+	// Find the innermost blossom of every vertex
+	innermost := make([]int, nvertex)
+	for v := range innermost {
+		innermost[v] = -1
+	}
+	depth := func(b int) int {
+		d := 0
+		for ; b != -1; b = result.Blossoms[b].Parent {
+			d++
+		}
+		return d
+	}
+	for b, blossom := range result.Blossoms {
+		if blossom.Parent < -1 || blossom.Parent >= len(result.Blossoms) {
+			return fmt.Errorf("%w: blossom %d has invalid parent %d", ErrNotOptimal, b, blossom.Parent)
+		}
+		for _, v := range blossom.Vertices {
+			if v < 0 || v >= int64(nvertex) {
+				return fmt.Errorf("%w: blossom %d contains unknown vertex %d", ErrNotOptimal, b, v)
+			}
+			if innermost[v] == -1 || depth(b) > depth(innermost[v]) {
+				innermost[v] = b
+			}
+		}
+	}
+	blossomChain := func(v int64) []int {
+		chain := make([]int, 0)
+		for b := innermost[v]; b != -1; b = result.Blossoms[b].Parent {
+			chain = append(chain, b)
+		}
+		// Outermost blossom first
+		for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+			chain[i], chain[j] = chain[j], chain[i]
+		}
+		return chain
+	}
+
+	// In maximum cardinality mode the vertex duals are shifted so that the smallest one is zero,
+	// which proves optimality among the matchings with the same number of edges
+	vdualoffset := ar.Zero()
+	if result.MaxCardinality && nvertex > 0 {
+		vdualoffset = ar.Sub(ar.Zero(), result.VertexDuals[0])
+		for _, d := range result.VertexDuals {
+			if ar.Cmp(ar.Sub(ar.Zero(), d), vdualoffset) > 0 {
+				vdualoffset = ar.Sub(ar.Zero(), d)
+			}
+		}
+	}
+	for v, d := range result.VertexDuals {
+		if ar.Cmp(ar.Add(d, vdualoffset), ar.Zero()) < 0 {
+			return fmt.Errorf("%w: vertex %d has negative dual", ErrNotOptimal, v)
+		}
+	}
+	for b, blossom := range result.Blossoms {
+		if ar.Cmp(blossom.Dual, ar.Zero()) < 0 {
+			return fmt.Errorf("%w: blossom %d has negative dual", ErrNotOptimal, b)
+		}
+	}
+
+	// All edges have non-negative slack and matched edges have zero slack
+	for k, edge := range edges {
+		if edge.Node1 >= int64(nvertex) || edge.Node2 >= int64(nvertex) {
+			return fmt.Errorf("%w: edge %d has no vertex duals", ErrNotOptimal, k)
+		}
+		s := ar.Sub(ar.Add(result.VertexDuals[edge.Node1], result.VertexDuals[edge.Node2]), ar.Double(edge.Weight))
+		iblossoms := blossomChain(edge.Node1)
+		jblossoms := blossomChain(edge.Node2)
+		for i := 0; i < len(iblossoms) && i < len(jblossoms) && iblossoms[i] == jblossoms[i]; i++ {
+			s = ar.Add(s, ar.Double(result.Blossoms[iblossoms[i]].Dual))
+		}
+		if ar.Cmp(s, ar.Zero()) < 0 {
+			return fmt.Errorf("%w: edge %d has negative slack %v", ErrNotOptimal, k, s)
+		}
+		if mateedge[edge.Node1] == k || mateedge[edge.Node2] == k {
+			if mateedge[edge.Node1] != k || mateedge[edge.Node2] != k {
+				return fmt.Errorf("%w: edge %d is matched at one end only", ErrNotOptimal, k)
+			}
+			if ar.Cmp(s, ar.Zero()) != 0 {
+				return fmt.Errorf("%w: matched edge %d has slack %v", ErrNotOptimal, k, s)
+			}
+		}
+	}
+
+	// Unmatched vertices have zero dual
+	for v := 0; v < nvertex; v++ {
+		if mateedge[v] == -1 && ar.Cmp(ar.Add(result.VertexDuals[v], vdualoffset), ar.Zero()) != 0 {
+			return fmt.Errorf("%w: unmatched vertex %d has non-zero dual", ErrNotOptimal, v)
+		}
+	}
+
+	// Blossoms with positive dual are full
+	for b, blossom := range result.Blossoms {
+		if ar.Cmp(blossom.Dual, ar.Zero()) <= 0 {
+			continue
+		}
+		if len(blossom.Vertices)%2 != 1 {
+			return fmt.Errorf("%w: blossom %d has an even number of vertices", ErrNotOptimal, b)
+		}
+		inside := make(map[int64]bool, len(blossom.Vertices))
+		for _, v := range blossom.Vertices {
+			inside[v] = true
+		}
+		matchedInside := 0
+		for _, v := range blossom.Vertices {
+			if k := mateedge[v]; k != -1 && inside[edges[k].Node1] && inside[edges[k].Node2] {
+				matchedInside++
+			}
+		}
+		if matchedInside != len(blossom.Vertices)-1 {
+			return fmt.Errorf("%w: blossom %d with positive dual is not full", ErrNotOptimal, b)
+		}
+	}
+
+	return nil
+}
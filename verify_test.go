@@ -0,0 +1,179 @@
+package mwm
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// randomEdges - helper function generating a random simple graph
+func randomEdges(rng *rand.Rand, nvertex int, density float64, minWeight, maxWeight int64) []GraphEdge {
+	edges := make([]GraphEdge, 0)
+	for i := 0; i < nvertex; i++ {
+		for j := i + 1; j < nvertex; j++ {
+			if rng.Float64() < density {
+				weight := minWeight + rng.Int63n(maxWeight-minWeight+1)
+				edges = append(edges, GraphEdge{Node1: int64(i), Node2: int64(j), Weight: weight})
+			}
+		}
+	}
+	return edges
+}
+
+// bruteForceMatching - helper function computing the optimum of a small graph by exhaustive search.
+// Returns the cardinality and weight of the best matching (maximum cardinality first if maxCardinality)
+func bruteForceMatching[W int64 | float64](nvertex int, edges []WeightedEdge[W], maxCardinality bool) (int, W) {
+	type best struct {
+		cardinality int
+		weight      W
+	}
+	memo := make(map[int]best)
+	var search func(used int) best
+	search = func(used int) best {
+		if b, ok := memo[used]; ok {
+			return b
+		}
+		v := 0
+		for v < nvertex && used&(1<<v) != 0 {
+			v++
+		}
+		if v == nvertex {
+			return best{}
+		}
+		result := search(used | 1<<v)
+		for _, edge := range edges {
+			i, j := int(edge.Node1), int(edge.Node2)
+			if j == v {
+				i, j = j, i
+			}
+			if i != v || used&(1<<j) != 0 {
+				continue
+			}
+			sub := search(used | 1<<i | 1<<j)
+			candidate := best{cardinality: sub.cardinality + 1, weight: sub.weight + edge.Weight}
+			if maxCardinality && candidate.cardinality != result.cardinality {
+				if candidate.cardinality > result.cardinality {
+					result = candidate
+				}
+			} else if candidate.weight > result.weight {
+				result = candidate
+			}
+		}
+		memo[used] = result
+		return result
+	}
+	b := search(0)
+	return b.cardinality, b.weight
+}
+
+// forEachMatching - helper function calling visit for every matching among a few edges, given as a bit set
+// of edge indices with its covered vertices, cardinality and weight
+func forEachMatching(edges []GraphEdge, visit func(subset int, covered map[int64]bool, cardinality int, weight int64)) {
+	for subset := 0; subset < 1<<len(edges); subset++ {
+		covered := make(map[int64]bool)
+		matching, cardinality, weight := true, 0, int64(0)
+		for k, edge := range edges {
+			if subset&(1<<k) == 0 {
+				continue
+			}
+			if covered[edge.Node1] || covered[edge.Node2] {
+				matching = false
+			}
+			covered[edge.Node1], covered[edge.Node2] = true, true
+			cardinality++
+			weight += edge.Weight
+		}
+		if matching {
+			visit(subset, covered, cardinality, weight)
+		}
+	}
+}
+
+// checkOptimum - helper function failing the test if a matching of the given cardinality and weight misses
+// the expected optimum in weight, or with maxCardinality in cardinality
+func checkOptimum(t *testing.T, where string, edges []GraphEdge, maxCardinality bool, expectedCardinality int, expectedWeight int64, cardinality int, weight int64) {
+	t.Helper()
+	if weight != expectedWeight || (maxCardinality && cardinality != expectedCardinality) {
+		t.Fatalf("%s (maxCardinality=%t): expected %d edges of weight %d, got %d edges of weight %d\nedges: %v",
+			where, maxCardinality, expectedCardinality, expectedWeight, cardinality, weight, edges)
+	}
+}
+
+// checkBruteForce - helper function comparing a matching of a small graph with exhaustive search like checkOptimum
+func checkBruteForce(t *testing.T, where string, nvertex int, edges []GraphEdge, maxCardinality bool, cardinality int, weight int64) {
+	t.Helper()
+	expectedCardinality, expectedWeight := bruteForceMatching(nvertex, edges, maxCardinality)
+	checkOptimum(t, where, edges, maxCardinality, expectedCardinality, expectedWeight, cardinality, weight)
+}
+
+// TestBruteForceRandom - test random graphs against exhaustive search
+func TestBruteForceRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	matcher := NewMaximumWeightedMatching()
+	for i := 0; i < 500; i++ {
+		nvertex := 2 + rng.Intn(12)
+		edges := randomEdges(rng, nvertex, 0.2+0.7*rng.Float64(), -30, 60)
+		maxCardinality := i%2 == 1
+		result := matcher.MaxWeightMatchingResult(edges, maxCardinality)
+		checkBruteForce(t, fmt.Sprintf("Iteration %d", i), nvertex, edges, maxCardinality, result.Cardinality, result.TotalWeight)
+	}
+}
+
+// TestVerifyOptimumKnownGraphs - test that the certificate holds for the blossom test graphs
+func TestVerifyOptimumKnownGraphs(t *testing.T) {
+	graphs := [][]GraphEdge{
+		{{Node1: 1, Node2: 2, Weight: 8}, {Node1: 1, Node2: 3, Weight: 9}, {Node1: 2, Node2: 3, Weight: 10}, {Node1: 3, Node2: 4, Weight: 7}},
+		{{Node1: 1, Node2: 2, Weight: 2}, {Node1: 1, Node2: 3, Weight: -2}, {Node1: 2, Node2: 3, Weight: 1}, {Node1: 2, Node2: 4, Weight: -1}, {Node1: 3, Node2: 4, Weight: -6}},
+		{
+			{Node1: 1, Node2: 2, Weight: 45}, {Node1: 1, Node2: 7, Weight: 45}, {Node1: 2, Node2: 3, Weight: 50},
+			{Node1: 3, Node2: 4, Weight: 45}, {Node1: 4, Node2: 5, Weight: 95}, {Node1: 4, Node2: 6, Weight: 94},
+			{Node1: 5, Node2: 6, Weight: 94}, {Node1: 6, Node2: 7, Weight: 50}, {Node1: 1, Node2: 8, Weight: 30},
+			{Node1: 3, Node2: 11, Weight: 35}, {Node1: 5, Node2: 9, Weight: 36}, {Node1: 7, Node2: 10, Weight: 26},
+			{Node1: 11, Node2: 12, Weight: 5},
+		},
+	}
+	matcher := NewMaximumWeightedMatching()
+	for i, edges := range graphs {
+		for _, maxCardinality := range []bool{false, true} {
+			result := matcher.MaxWeightMatchingResult(edges, maxCardinality)
+			if err := VerifyOptimum(edges, result); err != nil {
+				t.Errorf("Graph %d (maxCardinality=%t): %v", i, maxCardinality, err)
+			}
+		}
+	}
+}
+
+// TestVerifyOptimumRandom - test that the certificate holds for random graphs
+func TestVerifyOptimumRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	matcher := NewMaximumWeightedMatching()
+	for i := 0; i < 300; i++ {
+		edges := randomEdges(rng, 2+rng.Intn(14), 0.2+0.6*rng.Float64(), -20, 50)
+		maxCardinality := i%2 == 1
+		result := matcher.MaxWeightMatchingResult(edges, maxCardinality)
+		if err := VerifyOptimum(edges, result); err != nil {
+			t.Fatalf("Iteration %d (maxCardinality=%t): %v\nedges: %v", i, maxCardinality, err, edges)
+		}
+	}
+}
+
+// TestVerifyOptimumRejectsSuboptimal - test that a tampered result is rejected
+func TestVerifyOptimumRejectsSuboptimal(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{
+		{Node1: 0, Node2: 1, Weight: 10},
+		{Node1: 1, Node2: 2, Weight: 11},
+		{Node1: 2, Node2: 3, Weight: 12},
+		{Node1: 0, Node2: 3, Weight: 13},
+	}
+	result := matcher.MaxWeightMatchingResult(edges, false)
+
+	// Replace the optimal matching with the lighter one
+	result.Pairs = []Pair{{First: 0, Second: 1}, {First: 2, Second: 3}}
+	result.EdgeIndices = []int{0, 2}
+	result.Mate = []int64{1, 0, 3, 2}
+	if err := VerifyOptimum(edges, result); !errors.Is(err, ErrNotOptimal) {
+		t.Errorf("Expected ErrNotOptimal, got %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package mwm
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// WarmStart seeds the solver with a matching and, optionally, vertex duals, typically those of
+// a previous result on a slightly different graph. Pairs whose edge no longer exists are dropped,
+// and pairs that cannot be kept without violating dual feasibility are unmatched before the
+// algorithm continues, so any WarmStart yields an optimal result
+type WarmStart[W any] struct {
+	// Mate holds the mate of every vertex, or -1, like WeightedMatchingResult.Mate
+	Mate []int64
+	// VertexDuals, if not nil, holds vertex duals in the doubled units of WeightedMatchingResult.VertexDuals.
+	// The duals of a matched pair are used if they make the matching edge tight
+	VertexDuals []W
+}
+
+// MaxWeightMatchingWarm is MaxWeightMatchingE starting from the matching and duals of start
+// instead of an empty matching. Re-solving a graph that changed only in a few edges then takes
+// about as many stages as there are pairs to repair. A nil start solves from scratch
+func (mwm *MaximumWeightedMatching) MaxWeightMatchingWarm(edges []GraphEdge, maxCardinality bool, start *WarmStart[int64]) (*MatchingResult, error) {
+	if err := validateEdges(Int64Arithmetic{}, edges); err != nil {
+		return nil, err
+	}
+	if err := validateWarmStart(start); err != nil {
+		return nil, err
+	}
+
+	opts := solveOptions{maxCardinality: maxCardinality}
+	return int64Fallback(edges,
+		func() (*MatchingResult, error) {
+			return solveFrom(mwm, Int64Arithmetic{}, edges, opts, start)
+		},
+		func(edges []WeightedEdge[*big.Int]) (*WeightedMatchingResult[*big.Int], error) {
+			return solveFrom(mwm, BigIntArithmetic{}, edges, opts, bigWarmStart(start))
+		})
+}
+
+// MaxWeightMatchingWarmWith is MaxWeightMatchingWarm for weights of type W, computing with the operations of ar
+func MaxWeightMatchingWarmWith[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W], edges []WeightedEdge[W], maxCardinality bool, start *WarmStart[W]) (*WeightedMatchingResult[W], error) {
+	if err := validateEdges(ar, edges); err != nil {
+		return nil, err
+	}
+	if err := validateWarmStart(start); err != nil {
+		return nil, err
+	}
+	return solveFrom(mwm, ar, edges, solveOptions{maxCardinality: maxCardinality}, start)
+}
+
+// validateWarmStart checks that the mate array of start is symmetric
+func validateWarmStart[W any](start *WarmStart[W]) error {
+	if start == nil {
+		return nil
+	}
+	for v, w := range start.Mate {
+		if w < -1 || w == int64(v) || (w >= 0 && (w >= int64(len(start.Mate)) || start.Mate[w] != int64(v))) {
+			return fmt.Errorf("%w: vertex %d has mate %d", ErrInvalidWarmStart, v, w)
+		}
+	}
+	return nil
+}
+
+// bigWarmStart converts an int64 warm start to big integers
+func bigWarmStart(start *WarmStart[int64]) *WarmStart[*big.Int] {
+	if start == nil {
+		return nil
+	}
+	converted := &WarmStart[*big.Int]{Mate: start.Mate}
+	if start.VertexDuals != nil {
+		converted.VertexDuals = make([]*big.Int, len(start.VertexDuals))
+		for v, d := range start.VertexDuals {
+			converted.VertexDuals[v] = big.NewInt(d)
+		}
+	}
+	return converted
+}
+
+// seed replaces the empty matching of a freshly initialized engine by the matching of start.
+//
+// Every seeded pair gets the duals of start if they make its edge tight, or half its weight each.
+// A pair is unmatched if an edge between two matched vertices is infeasible, or, without maximum
+// cardinality, if a dual is negative. Each free vertex then gets the smallest dual feasible on its
+// edges, but at least the floor: zero, or in maximum cardinality mode the smallest matched dual.
+// The free vertices above the floor are the roots of the following stages, which lower them to
+// the floor in the way the cold start lowers all vertices together. Their distances to the
+// floor are made even, so that S-vertices of different trees keep an even slack
+func (e *engine[W]) seed(start *WarmStart[W]) {
+	ar := e.ar
+	nvertex := e.nvertex
+	dualvar := e.dualvar
+	// The index of the seeded edge of every vertex, or -1
+	seeded := e.mateedge
+	for v := 0; v < nvertex; v++ {
+		seeded[v] = -1
+	}
+
+	// Pick the heaviest edge between every pair of seeded mates
+	for k, edge := range e.edges {
+		i, j := edge.Node1, edge.Node2
+		if i >= int64(len(start.Mate)) || start.Mate[i] != j {
+			continue
+		}
+		if seeded[i] == -1 || ar.Cmp(edge.Weight, e.edges[seeded[i]].Weight) > 0 {
+			seeded[i] = k
+			seeded[j] = k
+		}
+	}
+	for v := 0; v < nvertex; v++ {
+		k := seeded[v]
+		if k == -1 || e.edges[k].Node1 != int64(v) {
+			continue
+		}
+		i, j, weight := e.edges[k].Node1, e.edges[k].Node2, e.edges[k].Weight
+		if max(i, j) < int64(len(start.VertexDuals)) {
+			// Share the slack of the edge between its endpoints
+			slack := ar.Sub(ar.Add(start.VertexDuals[i], start.VertexDuals[j]), ar.Double(weight))
+			half, _ := ar.Half(slack)
+			dualvar[i] = ar.Sub(start.VertexDuals[i], half)
+			dualvar[j] = ar.Sub(ar.Double(weight), dualvar[i])
+		} else {
+			dualvar[i] = weight
+			dualvar[j] = weight
+		}
+	}
+
+	unmatch := func(v int64) {
+		edge := e.edges[seeded[v]]
+		seeded[edge.Node1] = -1
+		seeded[edge.Node2] = -1
+	}
+	if !e.opts.maxCardinality {
+		for v := 0; v < nvertex; v++ {
+			if seeded[v] != -1 && ar.Cmp(dualvar[v], ar.Zero()) < 0 {
+				unmatch(int64(v))
+			}
+		}
+	}
+	for k, edge := range e.edges {
+		i, j := edge.Node1, edge.Node2
+		if seeded[i] != -1 && seeded[j] != -1 && seeded[i] != k && ar.Cmp(e.slack(k), ar.Zero()) < 0 {
+			unmatch(i)
+		}
+	}
+
+	floor := ar.Zero()
+	if e.opts.maxCardinality {
+		first := true
+		for v := 0; v < nvertex; v++ {
+			if seeded[v] != -1 && (first || ar.Cmp(dualvar[v], floor) < 0) {
+				floor = dualvar[v]
+				first = false
+			}
+		}
+	}
+
+	// Free vertices are assigned in order, each covering its edges to matched and earlier free vertices
+	e.floor = floor
+	for v := 0; v < nvertex; v++ {
+		if seeded[v] != -1 {
+			if e.edges[seeded[v]].Node1 == int64(v) {
+				e.mate[v] = int64(2*seeded[v] + 1)
+			} else {
+				e.mate[v] = int64(2 * seeded[v])
+			}
+			continue
+		}
+		d := floor
+		for _, p := range e.neighbors(v) {
+			w := e.endpoint[p]
+			if seeded[w] == -1 && w > int64(v) {
+				continue
+			}
+			if need := ar.Sub(ar.Double(e.edges[p/2].Weight), dualvar[w]); ar.Cmp(need, d) > 0 {
+				d = need
+			}
+		}
+		dualvar[v] = e.evenAboveFloor(d)
+	}
+
+	e.warm = true
+	e.maxCardinality = false
+}
+
+// evenAboveFloor rounds the dual d of a free vertex up to an even distance above the floor
+func (e *engine[W]) evenAboveFloor(d W) W {
+	ar := e.ar
+	if above := ar.Sub(d, e.floor); ar.Cmp(above, ar.Zero()) > 0 {
+		if half, exact := ar.Half(above); !exact {
+			d = ar.Add(e.floor, ar.Sub(ar.Double(above), ar.Double(half)))
+		}
+	}
+	return d
+}
@@ -0,0 +1,178 @@
+package mwm
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// perturbEdges - helper function changing the weights of a few edges and dropping one
+func perturbEdges(rng *rand.Rand, edges []GraphEdge, changes int, minWeight, maxWeight int64) []GraphEdge {
+	perturbed := append([]GraphEdge(nil), edges...)
+	if len(perturbed) == 0 {
+		return perturbed
+	}
+	for c := 0; c < changes; c++ {
+		perturbed[rng.Intn(len(perturbed))].Weight = minWeight + rng.Int63n(maxWeight-minWeight+1)
+	}
+	drop := rng.Intn(len(perturbed))
+	return append(perturbed[:drop], perturbed[drop+1:]...)
+}
+
+// TestWarmStartRandom - test warm starts from previous results against exhaustive search
+func TestWarmStartRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(15))
+	matcher := NewMaximumWeightedMatching()
+	for i := 0; i < 400; i++ {
+		nvertex := 2 + rng.Intn(11)
+		maxCardinality := i%2 == 1
+		edges := randomEdges(rng, nvertex, 0.2+0.7*rng.Float64(), -20, 50)
+		previous, err := matcher.MaxWeightMatchingE(edges, maxCardinality)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+
+		changed := perturbEdges(rng, edges, 1+rng.Intn(3), -20, 50)
+		start := &WarmStart[int64]{Mate: previous.Mate}
+		if i%4 < 2 {
+			start.VertexDuals = previous.VertexDuals
+		}
+		result, err := matcher.MaxWeightMatchingWarm(changed, maxCardinality, start)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		cardinality, weight := bruteForceMatching(nvertex, changed, maxCardinality)
+		if result.TotalWeight != weight || (maxCardinality && result.Cardinality != cardinality) {
+			t.Fatalf("Iteration %d: expected %d edges of weight %d, got %d edges of weight %d\nedges: %v\nstart: %v",
+				i, cardinality, weight, result.Cardinality, result.TotalWeight, changed, start)
+		}
+		if err := VerifyOptimum(changed, result); err != nil {
+			t.Fatalf("Iteration %d: %v", i, err)
+		}
+	}
+}
+
+// TestWarmStartStages - test that re-solving a slightly changed graph takes fewer stages than a cold start
+func TestWarmStartStages(t *testing.T) {
+	rng := rand.New(rand.NewSource(16))
+	matcher := NewMaximumWeightedMatching()
+	matcher.CollectStats = true
+	edges := randomEdges(rng, 300, 0.05, 1, 1000)
+	previous, err := matcher.MaxWeightMatchingE(edges, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	coldStages, warmStages := 0, 0
+	for i := 0; i < 10; i++ {
+		changed := perturbEdges(rng, edges, 3, 1, 1000)
+		cold, err := matcher.MaxWeightMatchingE(changed, false)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		warm, err := matcher.MaxWeightMatchingWarm(changed, false, &WarmStart[int64]{Mate: previous.Mate, VertexDuals: previous.VertexDuals})
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if warm.TotalWeight != cold.TotalWeight {
+			t.Fatalf("Iteration %d: warm start found weight %d, cold start %d", i, warm.TotalWeight, cold.TotalWeight)
+		}
+		coldStages += cold.Stats.Stages
+		warmStages += warm.Stats.Stages
+	}
+	if 4*warmStages > coldStages {
+		t.Errorf("Expected far fewer stages with a warm start, got %d warm and %d cold", warmStages, coldStages)
+	}
+}
+
+// TestWarmStartOptimal - test that a warm start from the optimum of the same graph only re-solves
+// the pairs held together by blossom duals, which WarmStart does not carry over
+func TestWarmStartOptimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(17))
+	matcher := NewMaximumWeightedMatching()
+	matcher.CollectStats = true
+	for _, maxCardinality := range []bool{false, true} {
+		edges := randomEdges(rng, 60, 0.2, 1, 100)
+		previous, err := matcher.MaxWeightMatchingE(edges, maxCardinality)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		result, err := matcher.MaxWeightMatchingWarm(edges, maxCardinality, &WarmStart[int64]{Mate: previous.Mate, VertexDuals: previous.VertexDuals})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.TotalWeight != previous.TotalWeight || 3*result.Stats.Stages > previous.Stats.Stages {
+			t.Errorf("Expected weight %d in far fewer than %d stages, got weight %d in %d stages",
+				previous.TotalWeight, previous.Stats.Stages, result.TotalWeight, result.Stats.Stages)
+		}
+	}
+}
+
+// TestWarmStartUnrelated - test warm starts from the matchings of unrelated graphs
+func TestWarmStartUnrelated(t *testing.T) {
+	rng := rand.New(rand.NewSource(19))
+	matcher := NewMaximumWeightedMatching()
+	for i := 0; i < 200; i++ {
+		nvertex := 2 + rng.Intn(40)
+		maxCardinality := i%2 == 1
+		other, err := matcher.MaxWeightMatchingE(randomEdges(rng, nvertex, 0.3, -50, 100), !maxCardinality)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		edges := randomEdges(rng, nvertex, 0.1+0.5*rng.Float64(), -50, 100)
+		cold, err := matcher.MaxWeightMatchingE(edges, maxCardinality)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		result, err := matcher.MaxWeightMatchingWarm(edges, maxCardinality, &WarmStart[int64]{Mate: other.Mate, VertexDuals: other.VertexDuals})
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if result.TotalWeight != cold.TotalWeight || (maxCardinality && result.Cardinality != cold.Cardinality) {
+			t.Fatalf("Iteration %d: expected %d edges of weight %d, got %d edges of weight %d",
+				i, cold.Cardinality, cold.TotalWeight, result.Cardinality, result.TotalWeight)
+		}
+		if err := VerifyOptimum(edges, result); err != nil {
+			t.Fatalf("Iteration %d: %v", i, err)
+		}
+	}
+}
+
+// TestWarmStartFloat - test the generic entry point with floating-point weights
+func TestWarmStartFloat(t *testing.T) {
+	rng := rand.New(rand.NewSource(18))
+	matcher := NewMaximumWeightedMatching()
+	ar := Float64Arithmetic{Epsilon: DefaultEpsilon}
+	for i := 0; i < 200; i++ {
+		nvertex := 2 + rng.Intn(9)
+		edges := make([]FloatGraphEdge, 0)
+		for _, edge := range randomEdges(rng, nvertex, 0.6, 0, 1000) {
+			edges = append(edges, FloatGraphEdge{Node1: edge.Node1, Node2: edge.Node2, Weight: float64(edge.Weight) / 7})
+		}
+		previous, err := MaxWeightMatchingWith(matcher, ar, edges, false)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if len(edges) > 0 {
+			edges[rng.Intn(len(edges))].Weight = rng.Float64() * 150
+		}
+		result, err := MaxWeightMatchingWarmWith(matcher, ar, edges, false, &WarmStart[float64]{Mate: previous.Mate, VertexDuals: previous.VertexDuals})
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if _, weight := bruteForceMatching(nvertex, edges, false); ar.Cmp(result.TotalWeight, weight) != 0 {
+			t.Fatalf("Iteration %d: expected weight %v, got %v", i, weight, result.TotalWeight)
+		}
+	}
+}
+
+// TestInvalidWarmStart - test that an asymmetric mate array is rejected
+func TestInvalidWarmStart(t *testing.T) {
+	matcher := NewMaximumWeightedMatching()
+	edges := []GraphEdge{{Node1: 0, Node2: 1, Weight: 1}, {Node1: 1, Node2: 2, Weight: 1}}
+	for _, mate := range [][]int64{{1, 2, 1}, {0, -1}, {3, -1, -1}, {-2}} {
+		if _, err := matcher.MaxWeightMatchingWarm(edges, false, &WarmStart[int64]{Mate: mate}); !errors.Is(err, ErrInvalidWarmStart) {
+			t.Errorf("Expected ErrInvalidWarmStart for mate %v, got %v", mate, err)
+		}
+	}
+}
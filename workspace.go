@@ -0,0 +1,48 @@
+package mwm
+
+// Workspace keeps the buffers of the algorithm between calls. It honors all options of Matching
+// like MaxWeightMatchingWith. Once the buffers have grown to the size of the largest graph solved,
+// Mate does not allocate, unless tracing, logging, statistics or Scaling are enabled in Matching,
+// or DetectBipartite hands the graph to the bipartite solver. Overflows are reported as
+// ErrWeightOverflow without the big integer fallback of MaxWeightMatchingE. A Workspace must not
+// be used by several goroutines at once
+type Workspace[W any] struct {
+	// Matching holds the options of the algorithm
+	Matching *MaximumWeightedMatching
+	// Arithmetic computes with the weights
+	Arithmetic Arithmetic[W]
+	engine     engine[W]
+}
+
+// NewWorkspace creates a workspace running mwm with the operations of ar
+func NewWorkspace[W any](mwm *MaximumWeightedMatching, ar Arithmetic[W]) *Workspace[W] {
+	return &Workspace[W]{Matching: mwm, Arithmetic: ar}
+}
+
+// Mate returns the maximum weighted matching as the mate of every vertex, or -1 for an unmatched vertex.
+// The returned slice belongs to the workspace and is overwritten by the next call
+func (ws *Workspace[W]) Mate(edges []WeightedEdge[W], maxCardinality bool) (mate []int64, err error) {
+	if err := validateEdges(ws.Arithmetic, edges); err != nil {
+		return nil, err
+	}
+	defer recoverFailure(&err)
+
+	if sol := ws.engine.dispatch(ws.Matching, ws.Arithmetic, edges, solveOptions{maxCardinality: maxCardinality}, nil); sol != nil {
+		return sol.mate, nil
+	}
+	return ws.engine.mate, nil
+}
+
+// Solve is MaxWeightMatchingWith on the buffers of the workspace. The result does not share memory with the workspace
+func (ws *Workspace[W]) Solve(edges []WeightedEdge[W], maxCardinality bool) (result *WeightedMatchingResult[W], err error) {
+	if err := validateEdges(ws.Arithmetic, edges); err != nil {
+		return nil, err
+	}
+	defer recoverFailure(&err)
+
+	sol := ws.engine.dispatch(ws.Matching, ws.Arithmetic, edges, solveOptions{maxCardinality: maxCardinality}, nil)
+	if sol == nil {
+		sol = ws.engine.solution()
+	}
+	return newMatchingResult(ws.Arithmetic, edges, sol, maxCardinality), nil
+}
@@ -0,0 +1,117 @@
+package mwm
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// TestWorkspace - test that a reused workspace gives the same matchings as fresh runs
+func TestWorkspace(t *testing.T) {
+	rng := rand.New(rand.NewSource(14))
+	matcher := NewMaximumWeightedMatching()
+	workspace := NewWorkspace(matcher, Int64Arithmetic{})
+	for i := 0; i < 200; i++ {
+		edges := randomEdges(rng, 2+rng.Intn(40), 0.05+0.5*rng.Float64(), -10, 100)
+		maxCardinality := i%2 == 1
+		expected := matcher.MaxWeightMatchingResult(edges, maxCardinality)
+
+		mate, err := workspace.Mate(edges, maxCardinality)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if !reflect.DeepEqual(mate, expected.Mate) {
+			t.Fatalf("Iteration %d: expected mate %v, got %v", i, expected.Mate, mate)
+		}
+
+		result, err := workspace.Solve(edges, maxCardinality)
+		if err != nil {
+			t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Fatalf("Iteration %d: expected %v, got %v", i, expected.Pairs, result.Pairs)
+		}
+	}
+}
+
+// TestWorkspaceOptions - test that a workspace honors DetectBipartite and Scaling like MaxWeightMatchingE
+func TestWorkspaceOptions(t *testing.T) {
+	rng := rand.New(rand.NewSource(16))
+	for _, matcher := range []*MaximumWeightedMatching{{DetectBipartite: true}, {Scaling: true}} {
+		workspace := NewWorkspace(matcher, Int64Arithmetic{})
+		for i := 0; i < 50; i++ {
+			edges := randomEdges(rng, 2+rng.Intn(30), 0.2, -10, 1000)
+			if i%2 == 0 {
+				// Keep only the edges between even and odd vertices, which makes the graph bipartite
+				bipartite := edges[:0]
+				for _, edge := range edges {
+					if (edge.Node1+edge.Node2)%2 == 1 {
+						bipartite = append(bipartite, edge)
+					}
+				}
+				edges = bipartite
+			}
+			expected, err := matcher.MaxWeightMatchingE(edges, false)
+			if err != nil {
+				t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+			}
+			result, err := workspace.Solve(edges, false)
+			if err != nil {
+				t.Fatalf("Iteration %d: unexpected error: %v", i, err)
+			}
+			if !reflect.DeepEqual(result, expected) {
+				t.Fatalf("Iteration %d (options %+v): expected %v, got %v", i, *matcher, expected, result)
+			}
+		}
+	}
+}
+
+// TestWorkspaceAllocations - test that the steady state of a workspace does not allocate
+func TestWorkspaceAllocations(t *testing.T) {
+	rng := rand.New(rand.NewSource(15))
+	graphs := make([][]GraphEdge, 10)
+	for i := range graphs {
+		graphs[i] = randomEdges(rng, 20+rng.Intn(20), 0.3, -10, 100)
+	}
+	workspace := NewWorkspace(NewMaximumWeightedMatching(), Int64Arithmetic{})
+	for _, edges := range graphs {
+		if _, err := workspace.Mate(edges, false); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	i := 0
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := workspace.Mate(graphs[i%len(graphs)], i%2 == 1); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		i++
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %v per run", allocs)
+	}
+}
+
+// BenchmarkMaxWeightMatching - benchmark a fresh run per graph
+func BenchmarkMaxWeightMatching(b *testing.B) {
+	rng := rand.New(rand.NewSource(16))
+	edges := randomEdges(rng, 50, 0.2, 1, 1000)
+	matcher := NewMaximumWeightedMatching()
+	b.ReportAllocs()
+	for b.Loop() {
+		matcher.MaxWeightMatching(edges, false)
+	}
+}
+
+// BenchmarkWorkspace - benchmark runs reusing the buffers of a workspace
+func BenchmarkWorkspace(b *testing.B) {
+	rng := rand.New(rand.NewSource(16))
+	edges := randomEdges(rng, 50, 0.2, 1, 1000)
+	workspace := NewWorkspace(NewMaximumWeightedMatching(), Int64Arithmetic{})
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := workspace.Mate(edges, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}